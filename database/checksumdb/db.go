@@ -0,0 +1,289 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package checksumdb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+const checksumSize = crc32.Size
+
+var (
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+	_ database.Iterator = (*iterator)(nil)
+
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+
+	castagnoli = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// Database appends a CRC32C checksum to all values that are provided and
+// verifies it whenever a value is read. This detects values that were
+// corrupted by the underlying storage without reporting an error.
+type Database struct {
+	lock   sync.RWMutex
+	db     database.Database
+	closed bool
+}
+
+// New returns a new checksummed database
+func New(db database.Database) *Database {
+	return &Database{
+		db: db,
+	}
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	return db.db.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	checksummedValue, err := db.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return verify(checksummedValue)
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Put(key, checksum(value))
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &database.IteratorError{
+			Err: database.ErrClosed,
+		}
+	}
+	return &iterator{
+		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       db,
+	}
+}
+
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *Database) isClosed() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.closed
+}
+
+func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return db.db.HealthCheck(ctx)
+}
+
+type batch struct {
+	database.Batch
+
+	db  *Database
+	ops []database.BatchOp
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:   slices.Clone(key),
+		Value: slices.Clone(value),
+	})
+	return b.Batch.Put(key, checksum(value))
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:    slices.Clone(key),
+		Delete: true,
+	})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	return b.Batch.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.ops) > len(b.ops)*database.MaxExcessCapacityFactor {
+		b.ops = make([]database.BatchOp, 0, cap(b.ops)/database.CapacityReductionFactor)
+	} else {
+		b.ops = b.ops[:0]
+	}
+	b.Batch.Reset()
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type iterator struct {
+	database.Iterator
+	db *Database
+
+	val, key []byte
+	err      error
+}
+
+func (it *iterator) Next() bool {
+	// Short-circuit and set an error if the underlying database has been closed.
+	if it.db.isClosed() {
+		it.val = nil
+		it.key = nil
+		it.err = database.ErrClosed
+		return false
+	}
+
+	next := it.Iterator.Next()
+	if next {
+		val, err := verify(it.Iterator.Value())
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.val = val
+		it.key = it.Iterator.Key()
+	} else {
+		it.val = nil
+		it.key = nil
+	}
+	return next
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+func (it *iterator) Value() []byte {
+	return it.val
+}
+
+// checksum returns [value] followed by its checksum.
+func checksum(value []byte) []byte {
+	checksummedValue := make([]byte, len(value), len(value)+checksumSize)
+	copy(checksummedValue, value)
+	return binary.BigEndian.AppendUint32(checksummedValue, crc32.Checksum(value, castagnoli))
+}
+
+// verify returns a copy of the value in [checksummedValue] if its checksum is
+// valid.
+func verify(checksummedValue []byte) ([]byte, error) {
+	if len(checksummedValue) < checksumSize {
+		return nil, ErrChecksumMismatch
+	}
+	var (
+		valueSize = len(checksummedValue) - checksumSize
+		value     = checksummedValue[:valueSize]
+		expected  = binary.BigEndian.Uint32(checksummedValue[valueSize:])
+	)
+	if crc32.Checksum(value, castagnoli) != expected {
+		return nil, ErrChecksumMismatch
+	}
+	return slices.Clone(value), nil
+}
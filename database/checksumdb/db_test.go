@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package checksumdb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/dbtest"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+)
+
+func TestInterface(t *testing.T) {
+	for name, test := range dbtest.Tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, New(memdb.New()))
+		})
+	}
+}
+
+func TestInterfaceComposed(t *testing.T) {
+	for name, test := range dbtest.Tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, prefixdb.New([]byte("prefix"), New(memdb.New())))
+			test(t, versiondb.New(New(memdb.New())))
+		})
+	}
+}
+
+func FuzzKeyValue(f *testing.F) {
+	dbtest.FuzzKeyValue(f, New(memdb.New()))
+}
+
+func FuzzNewIteratorWithPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithPrefix(f, New(memdb.New()))
+}
+
+func FuzzNewIteratorWithStartAndPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithStartAndPrefix(f, New(memdb.New()))
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range dbtest.BenchmarkSizes {
+		keys, values := dbtest.SetupBenchmark(b, size[0], size[1], size[2])
+		for name, bench := range dbtest.Benchmarks {
+			b.Run(fmt.Sprintf("checksumdb_%d_pairs_%d_keys_%d_values_%s", size[0], size[1], size[2], name), func(b *testing.B) {
+				bench(b, New(memdb.New()), keys, values)
+			})
+		}
+	}
+}
+
+func TestCorruptionDetected(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func([]byte) []byte
+	}{
+		{
+			name: "value bit flipped",
+			corrupt: func(value []byte) []byte {
+				value[0] ^= 1
+				return value
+			},
+		},
+		{
+			name: "checksum bit flipped",
+			corrupt: func(value []byte) []byte {
+				value[len(value)-1] ^= 1
+				return value
+			},
+		},
+		{
+			name: "truncated",
+			corrupt: func(value []byte) []byte {
+				return value[:checksumSize-1]
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			var (
+				baseDB = memdb.New()
+				db     = prefixdb.New([]byte("prefix"), New(baseDB))
+				key    = []byte("key")
+				value  = []byte("value")
+			)
+			require.NoError(db.Put(key, value))
+
+			got, err := db.Get(key)
+			require.NoError(err)
+			require.Equal(value, got)
+
+			// Corrupt the raw value in the backing database.
+			rawIt := baseDB.NewIterator()
+			require.True(rawIt.Next())
+			var (
+				rawKey   = rawIt.Key()
+				rawValue = test.corrupt(rawIt.Value())
+			)
+			require.False(rawIt.Next())
+			require.NoError(rawIt.Error())
+			rawIt.Release()
+			require.NoError(baseDB.Put(rawKey, rawValue))
+
+			_, err = db.Get(key)
+			require.ErrorIs(err, ErrChecksumMismatch)
+
+			it := db.NewIterator()
+			defer it.Release()
+
+			require.False(it.Next())
+			require.ErrorIs(it.Error(), ErrChecksumMismatch)
+
+			// Corruption must not be reported for keys that are not present.
+			_, err = db.Get([]byte("missing"))
+			require.ErrorIs(err, database.ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compressdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/compression"
+)
+
+const (
+	// uncompressed is the header of values that are stored as provided.
+	uncompressed byte = iota
+	// zstdCompressed is the header of values that are stored compressed with
+	// zstd.
+	zstdCompressed
+
+	headerSize = 1
+
+	// minCompressionSize is the smallest value that will be considered for
+	// compression. Smaller values are unlikely to shrink.
+	minCompressionSize = 64
+
+	// Values are only ever written by this database, so the decompressed size
+	// is not used to protect against zip bombs.
+	maxValueSize = math.MaxInt64 - 1
+)
+
+var (
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+	_ database.Iterator = (*iterator)(nil)
+
+	errMissingHeader = errors.New("missing header")
+	errUnknownHeader = errors.New("unknown header")
+)
+
+// Database compresses all values that are provided. Values that do not shrink
+// when compressed are stored uncompressed.
+type Database struct {
+	lock       sync.RWMutex
+	compressor compression.Compressor
+	db         database.Database
+	closed     bool
+}
+
+// New returns a new compressed database
+func New(db database.Database) (*Database, error) {
+	compressor, err := compression.NewZstdCompressor(maxValueSize)
+	return &Database{
+		compressor: compressor,
+		db:         db,
+	}, err
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+	return db.db.Has(key)
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	compressedValue, err := db.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return db.decompress(compressedValue)
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	compressedValue, err := db.compress(value)
+	if err != nil {
+		return err
+	}
+	return db.db.Put(key, compressedValue)
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &database.IteratorError{
+			Err: database.ErrClosed,
+		}
+	}
+	return &iterator{
+		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       db,
+	}
+}
+
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *Database) isClosed() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.closed
+}
+
+func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return db.db.HealthCheck(ctx)
+}
+
+type batch struct {
+	database.Batch
+
+	db  *Database
+	ops []database.BatchOp
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:   slices.Clone(key),
+		Value: slices.Clone(value),
+	})
+	compressedValue, err := b.db.compress(value)
+	if err != nil {
+		return err
+	}
+	return b.Batch.Put(key, compressedValue)
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:    slices.Clone(key),
+		Delete: true,
+	})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	return b.Batch.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.ops) > len(b.ops)*database.MaxExcessCapacityFactor {
+		b.ops = make([]database.BatchOp, 0, cap(b.ops)/database.CapacityReductionFactor)
+	} else {
+		b.ops = b.ops[:0]
+	}
+	b.Batch.Reset()
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type iterator struct {
+	database.Iterator
+	db *Database
+
+	val, key []byte
+	err      error
+}
+
+func (it *iterator) Next() bool {
+	// Short-circuit and set an error if the underlying database has been closed.
+	if it.db.isClosed() {
+		it.val = nil
+		it.key = nil
+		it.err = database.ErrClosed
+		return false
+	}
+
+	next := it.Iterator.Next()
+	if next {
+		compressedValue := it.Iterator.Value()
+		val, err := it.db.decompress(compressedValue)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.val = val
+		it.key = it.Iterator.Key()
+	} else {
+		it.val = nil
+		it.key = nil
+	}
+	return next
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+func (it *iterator) Value() []byte {
+	return it.val
+}
+
+// compress returns [value] prefixed with a header describing how it is stored.
+// The value is only compressed if doing so reduces its size.
+func (db *Database) compress(value []byte) ([]byte, error) {
+	if len(value) >= minCompressionSize {
+		compressed, err := db.compressor.Compress(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(compressed) < len(value) {
+			return withHeader(zstdCompressed, compressed), nil
+		}
+	}
+	return withHeader(uncompressed, value), nil
+}
+
+func (db *Database) decompress(value []byte) ([]byte, error) {
+	if len(value) < headerSize {
+		return nil, errMissingHeader
+	}
+	switch header, body := value[0], value[headerSize:]; header {
+	case uncompressed:
+		return slices.Clone(body), nil
+	case zstdCompressed:
+		return db.compressor.Decompress(body)
+	default:
+		return nil, fmt.Errorf("%w: %d", errUnknownHeader, header)
+	}
+}
+
+func withHeader(header byte, body []byte) []byte {
+	value := make([]byte, headerSize+len(body))
+	value[0] = header
+	copy(value[headerSize:], body)
+	return value
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package compressdb
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/dbtest"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestInterface(t *testing.T) {
+	for name, test := range dbtest.Tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newDB(t))
+		})
+	}
+}
+
+func newDB(t testing.TB) database.Database {
+	db, err := New(memdb.New())
+	require.NoError(t, err)
+	return db
+}
+
+func FuzzKeyValue(f *testing.F) {
+	dbtest.FuzzKeyValue(f, newDB(f))
+}
+
+func FuzzNewIteratorWithPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithPrefix(f, newDB(f))
+}
+
+func FuzzNewIteratorWithStartAndPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithStartAndPrefix(f, newDB(f))
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range dbtest.BenchmarkSizes {
+		keys, values := dbtest.SetupBenchmark(b, size[0], size[1], size[2])
+		for name, bench := range dbtest.Benchmarks {
+			b.Run(fmt.Sprintf("compressdb_%d_pairs_%d_keys_%d_values_%s", size[0], size[1], size[2], name), func(b *testing.B) {
+				bench(b, newDB(b), keys, values)
+			})
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	rand := rand.New(rand.NewSource(0)) //#nosec G404
+
+	incompressible := make([]byte, 4*units.KiB)
+	_, _ = rand.Read(incompressible)
+
+	tests := []struct {
+		name           string
+		value          []byte
+		expectedHeader byte
+	}{
+		{
+			name:           "empty",
+			value:          []byte{},
+			expectedHeader: uncompressed,
+		},
+		{
+			name:           "small",
+			value:          []byte("small value"),
+			expectedHeader: uncompressed,
+		},
+		{
+			name:           "compressible",
+			value:          bytes.Repeat([]byte("avalanche"), 1024),
+			expectedHeader: zstdCompressed,
+		},
+		{
+			name:           "incompressible",
+			value:          incompressible,
+			expectedHeader: uncompressed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			baseDB := memdb.New()
+			db, err := New(baseDB)
+			require.NoError(err)
+
+			key := []byte("key")
+			require.NoError(db.Put(key, test.value))
+
+			value, err := db.Get(key)
+			require.NoError(err)
+			require.Equal(test.value, value)
+
+			storedValue, err := baseDB.Get(key)
+			require.NoError(err)
+			require.Equal(test.expectedHeader, storedValue[0])
+			require.LessOrEqual(len(storedValue), headerSize+len(test.value))
+
+			it := db.NewIterator()
+			defer it.Release()
+
+			require.True(it.Next())
+			require.Equal(key, it.Key())
+			require.Equal(test.value, it.Value())
+			require.False(it.Next())
+			require.NoError(it.Error())
+		})
+	}
+}
+
+func TestUnknownHeader(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	db, err := New(baseDB)
+	require.NoError(err)
+
+	key := []byte("key")
+	require.NoError(baseDB.Put(key, []byte{zstdCompressed + 1}))
+
+	_, err = db.Get(key)
+	require.ErrorIs(err, errUnknownHeader)
+
+	require.NoError(baseDB.Put(key, nil))
+
+	_, err = db.Get(key)
+	require.ErrorIs(err, errMissingHeader)
+
+	_, err = db.Get([]byte("missing"))
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func BenchmarkCompressibleValues(b *testing.B) {
+	for _, size := range []int{units.KiB, 64 * units.KiB, units.MiB} {
+		b.Run(fmt.Sprintf("%d_bytes", size), func(b *testing.B) {
+			var (
+				db    = newDB(b)
+				key   = []byte("key")
+				value = bytes.Repeat([]byte{1, 2, 3, 4}, size/4)
+			)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				require.NoError(b, db.Put(key, value))
+				_, err := db.Get(key)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
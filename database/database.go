@@ -84,6 +84,17 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// MultiGetter wraps the MultiGet method of a backing data store.
+type MultiGetter interface {
+	// MultiGet retrieves the values of the provided keys. The i-th returned
+	// value and error correspond to the i-th key. ErrNotFound is returned in
+	// the error slot of any key that is not present.
+	//
+	// Note: [keys] are safe to modify and read after calling MultiGet.
+	// The returned byte slices are safe to read, but cannot be modified.
+	MultiGet(keys [][]byte) ([][]byte, []error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
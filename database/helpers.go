@@ -4,6 +4,7 @@
 package database
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -137,12 +138,38 @@ func GetBool(db KeyValueReader, key []byte) (bool, error) {
 	return b[0] == BoolTrue, nil
 }
 
+// MultiGet returns the values of [keys] in [db]. The i-th returned value and
+// error correspond to the i-th key. If [db] implements MultiGetter, its
+// implementation is used. Otherwise, each key is read with Get.
+func MultiGet(db KeyValueReader, keys [][]byte) ([][]byte, []error) {
+	if db, ok := db.(MultiGetter); ok {
+		return db.MultiGet(keys)
+	}
+
+	var (
+		values = make([][]byte, len(keys))
+		errs   = make([]error, len(keys))
+	)
+	for i, key := range keys {
+		values[i], errs[i] = db.Get(key)
+	}
+	return values, errs
+}
+
 func Count(db Iteratee) (int, error) {
-	iterator := db.NewIterator()
+	return CountPrefix(db, nil)
+}
+
+// CountPrefix returns the number of keys in [db] that have the given [prefix].
+func CountPrefix(db Iteratee, prefix []byte) (int, error) {
+	iterator := db.NewIteratorWithStart(prefix)
 	defer iterator.Release()
 
 	count := 0
 	for iterator.Next() {
+		if !bytes.HasPrefix(iterator.Key(), prefix) {
+			break
+		}
 		count++
 	}
 	return count, iterator.Error()
@@ -4,6 +4,7 @@
 package database
 
 import (
+	"bytes"
 	"math/rand"
 	"slices"
 	"testing"
@@ -49,3 +50,167 @@ func TestSortednessUint32(t *testing.T) {
 	}
 	require.True(t, utils.IsSortedBytes(intBytes))
 }
+
+// testReader is an in-memory KeyValueReader.
+type testReader map[string][]byte
+
+func (r testReader) Has(key []byte) (bool, error) {
+	_, ok := r[string(key)]
+	return ok, nil
+}
+
+func (r testReader) Get(key []byte) ([]byte, error) {
+	value, ok := r[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+// testMultiGetter is a testReader that records calls to MultiGet.
+type testMultiGetter struct {
+	testReader
+	numCalls int
+}
+
+func (r *testMultiGetter) MultiGet(keys [][]byte) ([][]byte, []error) {
+	r.numCalls++
+	return MultiGet(r.testReader, keys)
+}
+
+func TestMultiGet(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		key1   = []byte("hello1")
+		value1 = []byte("world1")
+		key2   = []byte("hello2")
+		value2 = []byte("world2")
+		key3   = []byte("hello3")
+	)
+	db := testReader{
+		string(key1): value1,
+		string(key2): value2,
+	}
+
+	values, errs := MultiGet(db, nil)
+	require.Empty(values)
+	require.Empty(errs)
+
+	values, errs = MultiGet(db, [][]byte{key2, key3, key1, key3, key2})
+	require.Equal([][]byte{value2, nil, value1, nil, value2}, values)
+	require.Len(errs, 5)
+	require.NoError(errs[0])
+	require.ErrorIs(errs[1], ErrNotFound)
+	require.NoError(errs[2])
+	require.ErrorIs(errs[3], ErrNotFound)
+	require.NoError(errs[4])
+
+	// Databases that implement MultiGetter must be used directly.
+	multiGetter := &testMultiGetter{
+		testReader: db,
+	}
+	values, errs = MultiGet(multiGetter, [][]byte{key1})
+	require.Equal(1, multiGetter.numCalls)
+	require.Equal([][]byte{value1}, values)
+	require.Equal([]error{nil}, errs)
+}
+
+// testIteratee is an in-memory Iteratee over sorted keys that records the
+// iterators it creates.
+type testIteratee struct {
+	keys      [][]byte
+	iterators []*testIterator
+}
+
+func (i *testIteratee) NewIterator() Iterator {
+	return i.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (i *testIteratee) NewIteratorWithStart(start []byte) Iterator {
+	return i.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (i *testIteratee) NewIteratorWithPrefix(prefix []byte) Iterator {
+	return i.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (i *testIteratee) NewIteratorWithStartAndPrefix(start, prefix []byte) Iterator {
+	it := &testIterator{}
+	for _, key := range i.keys {
+		if bytes.Compare(key, start) >= 0 && bytes.HasPrefix(key, prefix) {
+			it.keys = append(it.keys, key)
+		}
+	}
+	i.iterators = append(i.iterators, it)
+	return it
+}
+
+type testIterator struct {
+	keys     [][]byte
+	key      []byte
+	released bool
+}
+
+func (i *testIterator) Next() bool {
+	if len(i.keys) == 0 {
+		i.key = nil
+		return false
+	}
+	i.key, i.keys = i.keys[0], i.keys[1:]
+	return true
+}
+
+func (*testIterator) Error() error {
+	return nil
+}
+
+func (i *testIterator) Key() []byte {
+	return i.key
+}
+
+func (*testIterator) Value() []byte {
+	return nil
+}
+
+func (i *testIterator) Release() {
+	i.released = true
+}
+
+func TestCountPrefix(t *testing.T) {
+	require := require.New(t)
+
+	count, err := CountPrefix(&testIteratee{}, []byte("hello"))
+	require.NoError(err)
+	require.Zero(count)
+
+	db := &testIteratee{
+		keys: [][]byte{
+			[]byte("a"),
+			[]byte("hello1"),
+			[]byte("hello2"),
+			[]byte("hellp"),
+			[]byte("z"),
+		},
+	}
+
+	count, err = CountPrefix(db, []byte("hello"))
+	require.NoError(err)
+	require.Equal(2, count)
+
+	count, err = CountPrefix(db, []byte("hellz"))
+	require.NoError(err)
+	require.Zero(count)
+
+	count, err = CountPrefix(db, nil)
+	require.NoError(err)
+	require.Equal(5, count)
+
+	count, err = Count(db)
+	require.NoError(err)
+	require.Equal(5, count)
+
+	for _, it := range db.iterators {
+		require.True(it.released)
+	}
+}
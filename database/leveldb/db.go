@@ -281,6 +281,31 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 	return value, updateError(err)
 }
 
+// MultiGet returns the values the keys map to in the database. All keys are
+// read from a single snapshot of the database.
+func (db *Database) MultiGet(keys [][]byte) ([][]byte, []error) {
+	var (
+		values = make([][]byte, len(keys))
+		errs   = make([]error, len(keys))
+	)
+	snapshot, err := db.DB.GetSnapshot()
+	if err != nil {
+		err = updateError(err)
+		for i := range errs {
+			errs[i] = err
+		}
+		return values, errs
+	}
+	defer snapshot.Release()
+
+	for i, key := range keys {
+		value, err := snapshot.Get(key, nil)
+		values[i] = value
+		errs[i] = updateError(err)
+	}
+	return values, errs
+}
+
 // Put sets the value of the provided key to the provided value
 func (db *Database) Put(key []byte, value []byte) error {
 	return updateError(db.DB.Put(key, value, nil))
@@ -29,6 +29,40 @@ func TestInterface(t *testing.T) {
 	}
 }
 
+func TestMultiGet(t *testing.T) {
+	require := require.New(t)
+
+	baseDB, err := New(t.TempDir(), nil, logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+	require.IsType(&Database{}, baseDB)
+	db := baseDB.(*Database)
+
+	var (
+		key1   = []byte("hello1")
+		value1 = []byte("world1")
+		key2   = []byte("hello2")
+		value2 = []byte("world2")
+		key3   = []byte("hello3")
+	)
+	require.NoError(db.Put(key1, value1))
+	require.NoError(db.Put(key2, value2))
+
+	values, errs := db.MultiGet(nil)
+	require.Empty(values)
+	require.Empty(errs)
+
+	values, errs = db.MultiGet([][]byte{key2, key3, key1})
+	require.Equal([][]byte{value2, nil, value1}, values)
+	require.Len(errs, 3)
+	require.NoError(errs[0])
+	require.ErrorIs(errs[1], database.ErrNotFound)
+	require.NoError(errs[2])
+
+	require.NoError(db.Close())
+	_, errs = db.MultiGet([][]byte{key1})
+	require.Equal([]error{database.ErrClosed}, errs)
+}
+
 func newDB(t testing.TB) database.Database {
 	folder := t.TempDir()
 	db, err := New(folder, nil, logging.NoLog{}, prometheus.NewRegistry())
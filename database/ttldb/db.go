@@ -0,0 +1,327 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ttldb
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var (
+	_ database.Database = (*Database)(nil)
+	_ database.Batch    = (*batch)(nil)
+	_ database.Iterator = (*iterator)(nil)
+
+	errMalformedValue = errors.New("malformed value")
+)
+
+// Database stores every value alongside the time at which it expires. Expired
+// values are treated as if they were not present and are lazily deleted when
+// they are read.
+type Database struct {
+	lock   sync.RWMutex
+	ttl    time.Duration
+	clock  *mockable.Clock
+	db     database.Database
+	closed bool
+}
+
+// New returns a new database where every value expires [ttl] after it was
+// written, as reported by [clock]. A value is expired from the instant its
+// expiry is reached.
+func New(ttl time.Duration, clock *mockable.Clock, db database.Database) *Database {
+	return &Database{
+		ttl:   ttl,
+		clock: clock,
+		db:    db,
+	}
+}
+
+func (db *Database) Has(key []byte) (bool, error) {
+	_, err := db.Get(key)
+	switch err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	if db.closed {
+		db.lock.RUnlock()
+		return nil, database.ErrClosed
+	}
+	value, expired, err := db.get(key)
+	db.lock.RUnlock()
+	if err != nil || !expired {
+		return value, err
+	}
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	// The value may have been overwritten while the lock was released.
+	value, expired, err = db.get(key)
+	if err != nil || !expired {
+		return value, err
+	}
+	if err := db.db.Delete(key); err != nil {
+		return nil, err
+	}
+	return nil, database.ErrNotFound
+}
+
+// get returns the value of [key] and whether it has expired. Assumes the lock
+// is held.
+func (db *Database) get(key []byte) ([]byte, bool, error) {
+	wrappedValue, err := db.db.Get(key)
+	if err != nil {
+		return nil, false, err
+	}
+	expiry, value, err := unwrap(wrappedValue)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, db.isExpired(expiry), nil
+}
+
+func (db *Database) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Put(key, db.wrap(value))
+}
+
+func (db *Database) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Delete(key)
+}
+
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		Batch: db.db.NewBatch(),
+		db:    db,
+	}
+}
+
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return &database.IteratorError{
+			Err: database.ErrClosed,
+		}
+	}
+	return &iterator{
+		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       db,
+	}
+}
+
+func (db *Database) Compact(start, limit []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return db.db.Compact(start, limit)
+}
+
+func (db *Database) Close() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	db.closed = true
+	return nil
+}
+
+func (db *Database) isClosed() bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return db.closed
+}
+
+func (db *Database) HealthCheck(ctx context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return db.db.HealthCheck(ctx)
+}
+
+type batch struct {
+	database.Batch
+
+	db  *Database
+	ops []database.BatchOp
+}
+
+func (b *batch) Put(key, value []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:   slices.Clone(key),
+		Value: slices.Clone(value),
+	})
+	return b.Batch.Put(key, b.db.wrap(value))
+}
+
+func (b *batch) Delete(key []byte) error {
+	b.ops = append(b.ops, database.BatchOp{
+		Key:    slices.Clone(key),
+		Delete: true,
+	})
+	return b.Batch.Delete(key)
+}
+
+func (b *batch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	return b.Batch.Write()
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	if cap(b.ops) > len(b.ops)*database.MaxExcessCapacityFactor {
+		b.ops = make([]database.BatchOp, 0, cap(b.ops)/database.CapacityReductionFactor)
+	} else {
+		b.ops = b.ops[:0]
+	}
+	b.Batch.Reset()
+}
+
+// Replay replays the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	for _, op := range b.ops {
+		if op.Delete {
+			if err := w.Delete(op.Key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.Key, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// iterator skips over any expired values. Expired values are not deleted
+// during iteration.
+type iterator struct {
+	database.Iterator
+	db *Database
+
+	val, key []byte
+	err      error
+}
+
+func (it *iterator) Next() bool {
+	// Short-circuit and set an error if the underlying database has been closed.
+	if it.db.isClosed() {
+		it.val = nil
+		it.key = nil
+		it.err = database.ErrClosed
+		return false
+	}
+
+	for it.Iterator.Next() {
+		expiry, val, err := unwrap(it.Iterator.Value())
+		if err != nil {
+			it.val = nil
+			it.key = nil
+			it.err = err
+			return false
+		}
+		if it.db.isExpired(expiry) {
+			continue
+		}
+		it.val = val
+		it.key = it.Iterator.Key()
+		return true
+	}
+	it.val = nil
+	it.key = nil
+	return false
+}
+
+func (it *iterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
+
+func (it *iterator) Key() []byte {
+	return it.key
+}
+
+func (it *iterator) Value() []byte {
+	return it.val
+}
+
+func (db *Database) isExpired(expiry uint64) bool {
+	return uint64(db.clock.Time().UnixNano()) >= expiry
+}
+
+// wrap prefixes [value] with the time at which it expires.
+func (db *Database) wrap(value []byte) []byte {
+	expiry := uint64(db.clock.Time().Add(db.ttl).UnixNano())
+	wrappedValue := make([]byte, database.Uint64Size+len(value))
+	copy(wrappedValue, database.PackUInt64(expiry))
+	copy(wrappedValue[database.Uint64Size:], value)
+	return wrappedValue
+}
+
+// unwrap splits [wrappedValue] into the time at which it expires and the
+// original value.
+func unwrap(wrappedValue []byte) (uint64, []byte, error) {
+	if len(wrappedValue) < database.Uint64Size {
+		return 0, nil, errMalformedValue
+	}
+	expiry, err := database.ParseUInt64(wrappedValue[:database.Uint64Size])
+	return expiry, wrappedValue[database.Uint64Size:], err
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ttldb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/dbtest"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+const testTTL = time.Hour
+
+func TestInterface(t *testing.T) {
+	for name, test := range dbtest.Tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newDB())
+		})
+	}
+}
+
+func newDB() database.Database {
+	return New(testTTL, &mockable.Clock{}, memdb.New())
+}
+
+func FuzzKeyValue(f *testing.F) {
+	dbtest.FuzzKeyValue(f, newDB())
+}
+
+func FuzzNewIteratorWithPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithPrefix(f, newDB())
+}
+
+func FuzzNewIteratorWithStartAndPrefix(f *testing.F) {
+	dbtest.FuzzNewIteratorWithStartAndPrefix(f, newDB())
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range dbtest.BenchmarkSizes {
+		keys, values := dbtest.SetupBenchmark(b, size[0], size[1], size[2])
+		for name, bench := range dbtest.Benchmarks {
+			b.Run(fmt.Sprintf("ttldb_%d_pairs_%d_keys_%d_values_%s", size[0], size[1], size[2], name), func(b *testing.B) {
+				bench(b, newDB(), keys, values)
+			})
+		}
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		clock     = &mockable.Clock{}
+		startTime = time.Unix(1_000_000, 0)
+		baseDB    = memdb.New()
+		db        = New(testTTL, clock, baseDB)
+
+		expiringKey = []byte("expiring")
+		freshKey    = []byte("fresh")
+		value       = []byte("value")
+	)
+	clock.Set(startTime)
+
+	require.NoError(db.Put(expiringKey, value))
+
+	clock.Set(startTime.Add(testTTL / 2))
+	require.NoError(db.Put(freshKey, value))
+
+	got, err := db.Get(expiringKey)
+	require.NoError(err)
+	require.Equal(value, got)
+
+	// Advance the clock to exactly when [expiringKey] expires.
+	clock.Set(startTime.Add(testTTL))
+
+	has, err := db.Has(freshKey)
+	require.NoError(err)
+	require.True(has)
+
+	// The expired value must be skipped during iteration but not deleted.
+	it := db.NewIterator()
+	require.True(it.Next())
+	require.Equal(freshKey, it.Key())
+	require.Equal(value, it.Value())
+	require.False(it.Next())
+	require.NoError(it.Error())
+	it.Release()
+
+	has, err = baseDB.Has(expiringKey)
+	require.NoError(err)
+	require.True(has)
+
+	// Reading the expired value must delete it.
+	_, err = db.Get(expiringKey)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	has, err = baseDB.Has(expiringKey)
+	require.NoError(err)
+	require.False(has)
+
+	// Writing the key again must reset its expiry.
+	require.NoError(db.Put(expiringKey, value))
+
+	// Advance the clock to exactly when [freshKey] expires.
+	clock.Set(startTime.Add(3 * testTTL / 2))
+
+	got, err = db.Get(expiringKey)
+	require.NoError(err)
+	require.Equal(value, got)
+
+	has, err = db.Has(freshKey)
+	require.NoError(err)
+	require.False(has)
+
+	// Advance the clock to exactly when the rewritten [expiringKey] expires.
+	clock.Set(startTime.Add(2 * testTTL))
+
+	_, err = db.Get(expiringKey)
+	require.ErrorIs(err, database.ErrNotFound)
+}
+
+func TestExpiryBatch(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		clock     = &mockable.Clock{}
+		startTime = time.Unix(1_000_000, 0)
+		db        = New(testTTL, clock, memdb.New())
+
+		key   = []byte("key")
+		value = []byte("value")
+	)
+	clock.Set(startTime)
+
+	batch := db.NewBatch()
+	require.NoError(batch.Put(key, value))
+
+	// The expiry is determined when the value is added to the batch.
+	clock.Set(startTime.Add(testTTL))
+	require.NoError(batch.Write())
+
+	_, err := db.Get(key)
+	require.ErrorIs(err, database.ErrNotFound)
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/cb58"
@@ -24,7 +25,35 @@ var (
 	// Empty is a useful all zero value
 	Empty = ID{}
 
-	errMissingQuotes = errors.New("first and last characters should be quotes")
+	errMissingQuotes    = errors.New("first and last characters should be quotes")
+	errUnknownEncoding  = errors.New("unknown encoding")
+	errMissingHexPrefix = errors.New("missing 0x prefix")
+
+	// flexibleEncodings are the encodings attempted, in order, when parsing
+	// user provided IDs.
+	flexibleEncodings = []struct {
+		name   string
+		decode func(string) ([]byte, error)
+	}{
+		{
+			name: "0x-prefixed hex",
+			decode: func(str string) ([]byte, error) {
+				hexStr, ok := strings.CutPrefix(str, "0x")
+				if !ok {
+					return nil, errMissingHexPrefix
+				}
+				return hex.DecodeString(hexStr)
+			},
+		},
+		{
+			name:   "hex",
+			decode: hex.DecodeString,
+		},
+		{
+			name:   "cb58",
+			decode: cb58.Decode,
+		},
+	}
 
 	_ utils.Sortable[ID] = ID{}
 )
@@ -46,6 +75,11 @@ func FromString(idStr string) (ID, error) {
 	return ToID(bytes)
 }
 
+// ParseIDFlexible parses an ID encoded as 0x-prefixed hex, hex, or cb58.
+func ParseIDFlexible(idStr string) (ID, error) {
+	return parseFlexible(idStr, ToID)
+}
+
 // FromStringOrPanic is the same as FromString, but will panic on error
 func FromStringOrPanic(idStr string) ID {
 	id, err := FromString(idStr)
@@ -55,6 +89,27 @@ func FromStringOrPanic(idStr string) ID {
 	return id
 }
 
+// parseFlexible returns the first successful conversion of [str], decoded with
+// each of the [flexibleEncodings], using [toID]. If every encoding fails, the
+// returned error describes each attempt.
+func parseFlexible[T any](str string, toID func([]byte) (T, error)) (T, error) {
+	errs := make([]error, 0, len(flexibleEncodings))
+	for _, encoding := range flexibleEncodings {
+		bytes, err := encoding.decode(str)
+		if err == nil {
+			var id T
+			id, err = toID(bytes)
+			if err == nil {
+				return id, nil
+			}
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", encoding.name, err))
+	}
+
+	var zero T
+	return zero, fmt.Errorf("%w %q: %w", errUnknownEncoding, str, errors.Join(errs...))
+}
+
 func (id ID) MarshalJSON() ([]byte, error) {
 	str, err := cb58.Encode(id[:])
 	if err != nil {
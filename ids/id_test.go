@@ -6,12 +6,14 @@ package ids
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/cb58"
+	"github.com/ava-labs/avalanchego/utils/hashing"
 )
 
 func TestID(t *testing.T) {
@@ -95,6 +97,130 @@ func TestIDFromStringError(t *testing.T) {
 	}
 }
 
+func TestParseIDFlexible(t *testing.T) {
+	id := ID{'a', 'v', 'a', ' ', 'l', 'a', 'b', 's'}
+	tests := []struct {
+		name        string
+		in          string
+		expectedErr error
+	}{
+		{
+			name: "cb58",
+			in:   id.String(),
+		},
+		{
+			name: "0x-prefixed hex",
+			in:   "0x" + id.Hex(),
+		},
+		{
+			name: "hex",
+			in:   id.Hex(),
+		},
+		{
+			name: "uppercase hex",
+			in:   strings.ToUpper(id.Hex()),
+		},
+		{
+			name:        "empty",
+			in:          "",
+			expectedErr: cb58.ErrBase58Decoding,
+		},
+		{
+			name:        "bad cb58 checksum",
+			in:          "foobar",
+			expectedErr: cb58.ErrBadChecksum,
+		},
+		{
+			name:        "short hex",
+			in:          id.Hex()[2:],
+			expectedErr: hashing.ErrInvalidHashLen,
+		},
+		{
+			name:        "short 0x-prefixed hex",
+			in:          "0x" + id.Hex()[2:],
+			expectedErr: hashing.ErrInvalidHashLen,
+		},
+		{
+			name:        "invalid hex",
+			in:          "0xzz",
+			expectedErr: errUnknownEncoding,
+		},
+		{
+			name:        "cb58 ShortID",
+			in:          ShortID{1}.String(),
+			expectedErr: hashing.ErrInvalidHashLen,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			parsedID, err := ParseIDFlexible(test.in)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				require.ErrorIs(err, errUnknownEncoding)
+				return
+			}
+			require.Equal(id, parsedID)
+		})
+	}
+}
+
+func TestParseShortIDFlexible(t *testing.T) {
+	id := ShortID{'a', 'v', 'a', ' ', 'l', 'a', 'b', 's'}
+	tests := []struct {
+		name        string
+		in          string
+		expectedErr error
+	}{
+		{
+			name: "cb58",
+			in:   id.String(),
+		},
+		{
+			name: "0x-prefixed hex",
+			in:   "0x" + id.Hex(),
+		},
+		{
+			name: "hex",
+			in:   id.Hex(),
+		},
+		{
+			name:        "empty",
+			in:          "",
+			expectedErr: cb58.ErrBase58Decoding,
+		},
+		{
+			name:        "bad cb58 checksum",
+			in:          "foobar",
+			expectedErr: cb58.ErrBadChecksum,
+		},
+		{
+			name:        "ID hex",
+			in:          ID{1}.Hex(),
+			expectedErr: hashing.ErrInvalidHashLen,
+		},
+		{
+			name:        "cb58 ID",
+			in:          ID{1}.String(),
+			expectedErr: hashing.ErrInvalidHashLen,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			parsedID, err := ParseShortIDFlexible(test.in)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				require.ErrorIs(err, errUnknownEncoding)
+				return
+			}
+			require.Equal(id, parsedID)
+		})
+	}
+}
+
 func TestIDMarshalJSON(t *testing.T) {
 	tests := []struct {
 		label string
@@ -4,12 +4,16 @@
 package ids
 
 import (
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ava-labs/avalanchego/staking"
 	"github.com/ava-labs/avalanchego/utils/cb58"
 )
 
@@ -216,3 +220,28 @@ func TestNodeIDCompare(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeIDFromCert(t *testing.T) {
+	require := require.New(t)
+
+	// The NodeID of staker1 is documented in staking/local/README.md.
+	certBytes, err := os.ReadFile("../staking/local/staker1.crt")
+	require.NoError(err)
+	block, _ := pem.Decode(certBytes)
+	require.NotNil(block)
+
+	cert, err := staking.ParseCertificate(block.Bytes)
+	require.NoError(err)
+
+	expectedNodeID, err := NodeIDFromString("NodeID-7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg")
+	require.NoError(err)
+	require.Equal(expectedNodeID, NodeIDFromCert(cert))
+
+	// The NodeID only depends on the raw certificate, so certificates parsed
+	// with the standard library result in the same NodeID.
+	x509Cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(err)
+	require.Equal(expectedNodeID, NodeIDFromCert(&staking.Certificate{
+		Raw: x509Cert.Raw,
+	}))
+}
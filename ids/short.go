@@ -40,6 +40,12 @@ func ShortFromString(idStr string) (ShortID, error) {
 	return ToShortID(bytes)
 }
 
+// ParseShortIDFlexible parses a ShortID encoded as 0x-prefixed hex, hex, or
+// cb58.
+func ParseShortIDFlexible(idStr string) (ShortID, error) {
+	return parseFlexible(idStr, ToShortID)
+}
+
 // ShortFromPrefixedString returns a ShortID assuming the cb58 format is
 // prefixed
 func ShortFromPrefixedString(idStr, prefix string) (ShortID, error) {
@@ -253,11 +253,12 @@ var _ = ginkgo.Describe("[Staking Rewards]", func() {
 		})
 
 		tc.By("checking expected rewards against actual rewards", func() {
-			var (
-				adminClient  = admin.NewClient(nodeURI.URI)
-				rewardConfig = getRewardConfig(tc, adminClient)
-				calculator   = reward.NewCalculator(rewardConfig)
+			adminClient := admin.NewClient(nodeURI.URI)
+			rewardConfig := getRewardConfig(tc, adminClient)
+			calculator, err := reward.NewCalculator(rewardConfig)
+			require.NoError(err)
 
+			var (
 				expectedValidationReward = calculator.Calculate(actualAlphaValidationPeriod, weight, supplyAtAlphaNodeStart)
 
 				potentialDelegationReward                      = calculator.Calculate(actualGammaDelegationPeriod, weight, supplyAtGammaDelegatorStart)
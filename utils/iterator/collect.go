@@ -0,0 +1,27 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+// Fallible is implemented by iterators whose iteration may fail. Once Next
+// returns false, Err reports whether the iteration was terminated early due to
+// an error.
+type Fallible interface {
+	Err() error
+}
+
+// CollectErr returns all of the elements of [it] in order and releases [it].
+// If [it] implements [Fallible] and reports an error, the error is returned
+// along with the elements collected before the failure.
+func CollectErr[T any](it Iterator[T]) ([]T, error) {
+	defer it.Release()
+
+	var values []T
+	for it.Next() {
+		values = append(values, it.Value())
+	}
+	if fallible, ok := it.(Fallible); ok {
+		return values, fallible.Err()
+	}
+	return values, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("non-nil error")
+
+// failing returns an error after yielding the first [failAfter] elements.
+type failing[T any] struct {
+	Iterator[T]
+	failAfter int
+	released  bool
+	err       error
+}
+
+func (i *failing[_]) Next() bool {
+	if i.failAfter == 0 {
+		i.err = errTest
+		return false
+	}
+	i.failAfter--
+	return i.Iterator.Next()
+}
+
+func (i *failing[_]) Release() {
+	i.released = true
+	i.Iterator.Release()
+}
+
+func (i *failing[_]) Err() error {
+	return i.err
+}
+
+func TestCollectErr(t *testing.T) {
+	require := require.New(t)
+
+	values, err := CollectErr(FromSlice(1, 2, 3))
+	require.NoError(err)
+	require.Equal([]int{1, 2, 3}, values)
+
+	values, err = CollectErr[int](Empty[int]{})
+	require.NoError(err)
+	require.Empty(values)
+
+	it := &failing[int]{
+		Iterator:  FromSlice(1, 2, 3),
+		failAfter: 2,
+	}
+	values, err = CollectErr[int](it)
+	require.ErrorIs(err, errTest)
+	require.Equal([]int{1, 2}, values)
+	require.True(it.released)
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import "context"
+
+var (
+	_ Iterator[any] = (*contextual[any])(nil)
+	_ Fallible      = (*contextual[any])(nil)
+)
+
+type contextual[T any] struct {
+	ctx   context.Context
+	it    Iterator[T]
+	err   error
+	guard releaseGuard
+}
+
+// WithContext returns an iterator that contains the elements of [it] until
+// [ctx] is cancelled. Once [ctx] is cancelled, Next returns false and Err
+// reports the error of [ctx].
+func WithContext[T any](ctx context.Context, it Iterator[T]) Iterator[T] {
+	return &contextual[T]{
+		ctx: ctx,
+		it:  it,
+	}
+}
+
+func (i *contextual[_]) Next() bool {
+	if i.err != nil {
+		return false
+	}
+	if err := i.ctx.Err(); err != nil {
+		i.err = err
+		return false
+	}
+	return i.it.Next()
+}
+
+func (i *contextual[T]) Value() T {
+	return i.it.Value()
+}
+
+func (i *contextual[_]) Err() error {
+	return i.err
+}
+
+func (i *contextual[_]) Release() {
+	if i.guard.release() {
+		i.it.Release()
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContext(t *testing.T) {
+	require := require.New(t)
+
+	values, err := CollectErr(WithContext(
+		context.Background(),
+		FromSlice(1, 2, 3),
+	))
+	require.NoError(err)
+	require.Equal([]int{1, 2, 3}, values)
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := WithContext[int](ctx, tracked)
+
+	require.True(it.Next())
+	require.Equal(1, it.Value())
+
+	cancel()
+	require.False(it.Next())
+	// Once aborted, the iterator must remain exhausted.
+	require.False(it.Next())
+
+	values, err := CollectErr(it)
+	require.ErrorIs(err, context.Canceled)
+	require.Empty(values)
+	require.True(tracked.released)
+}
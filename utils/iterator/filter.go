@@ -8,6 +8,7 @@ var _ Iterator[any] = (*filtered[any])(nil)
 type filtered[T any] struct {
 	it     Iterator[T]
 	filter func(T) bool
+	guard  releaseGuard
 }
 
 // Filter returns an iterator that skips the elements in [it] that return true
@@ -34,5 +35,7 @@ func (i *filtered[T]) Value() T {
 }
 
 func (i *filtered[_]) Release() {
-	i.it.Release()
+	if i.guard.release() {
+		i.it.Release()
+	}
 }
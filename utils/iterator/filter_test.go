@@ -1,7 +1,7 @@
 // Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
-package iterator_test
+package iterator
 
 import (
 	"testing"
@@ -10,7 +10,6 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/utils/iterator"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
 )
 
@@ -40,8 +39,8 @@ func TestFilter(t *testing.T) {
 		stakers[3].TxID: stakers[3],
 	}
 
-	it := iterator.Filter(
-		iterator.FromSlice(stakers[:3]...),
+	it := Filter(
+		FromSlice(stakers[:3]...),
 		func(staker *state.Staker) bool {
 			_, ok := maskedStakers[staker.TxID]
 			return ok
@@ -55,3 +54,48 @@ func TestFilter(t *testing.T) {
 	it.Release()
 	require.False(it.Next())
 }
+
+func TestFilterEmpty(t *testing.T) {
+	require := require.New(t)
+
+	it := Filter(
+		Empty[int]{},
+		func(int) bool {
+			return false
+		},
+	)
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestFilterAllFiltered(t *testing.T) {
+	require := require.New(t)
+
+	it := Filter(
+		FromSlice(1, 2, 3),
+		func(int) bool {
+			return true
+		},
+	)
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestFilterRelease(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := Filter[int](
+		tracked,
+		func(i int) bool {
+			return i%2 == 1
+		},
+	)
+
+	require.True(it.Next())
+	require.Equal(2, it.Value())
+	require.False(tracked.released)
+
+	it.Release()
+	require.True(tracked.released)
+}
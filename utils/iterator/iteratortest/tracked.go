@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iteratortest
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/ava-labs/avalanchego/utils/iterator"
+)
+
+var _ iterator.Iterator[any] = (*Tracked[any])(nil)
+
+// Tracked wraps an iterator to detect if it is garbage collected without
+// having been released.
+type Tracked[T any] struct {
+	iterator.Iterator[T]
+	released atomic.Bool
+}
+
+// NewTracked returns [it] wrapped in a [Tracked] iterator. If the returned
+// iterator is garbage collected without Release having been called, [onLeak]
+// is called from the finalizer goroutine.
+func NewTracked[T any](it iterator.Iterator[T], onLeak func()) *Tracked[T] {
+	tracked := &Tracked[T]{
+		Iterator: it,
+	}
+	runtime.SetFinalizer(tracked, func(tracked *Tracked[T]) {
+		if !tracked.released.Load() {
+			onLeak()
+		}
+	})
+	return tracked
+}
+
+func (t *Tracked[T]) Release() {
+	t.released.Store(true)
+	t.Iterator.Release()
+}
+
+// Released returns true if Release has been called.
+func (t *Tracked[T]) Released() bool {
+	return t.released.Load()
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iteratortest
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/iterator"
+)
+
+func TestTrackedLeaked(t *testing.T) {
+	var leaked atomic.Bool
+	func() {
+		it := NewTracked(iterator.FromSlice(1, 2, 3), func() {
+			leaked.Store(true)
+		})
+		require.True(t, it.Next())
+		require.Equal(t, 1, it.Value())
+	}()
+
+	require.Eventually(
+		t,
+		func() bool {
+			runtime.GC()
+			return leaked.Load()
+		},
+		time.Second,
+		10*time.Millisecond,
+	)
+}
+
+func TestTrackedReleased(t *testing.T) {
+	require := require.New(t)
+
+	var leaked atomic.Bool
+	func() {
+		it := NewTracked(iterator.FromSlice(1, 2, 3), func() {
+			leaked.Store(true)
+		})
+		require.True(it.Next())
+		it.Release()
+		require.True(it.Released())
+	}()
+
+	// Give the finalizer ample opportunity to run.
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	require.False(leaked.Load())
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+var _ Iterator[any] = (*limited[any])(nil)
+
+type limited[T any] struct {
+	it        Iterator[T]
+	remaining int
+	// itReleased is true once [it] has been released.
+	itReleased bool
+	guard      releaseGuard
+}
+
+// Limit returns an iterator that contains at most the first [n] elements of
+// [it]. [it] is released once Next reports that the limit has been reached.
+func Limit[T any](it Iterator[T], n int) Iterator[T] {
+	return &limited[T]{
+		it:        it,
+		remaining: n,
+	}
+}
+
+func (i *limited[_]) Next() bool {
+	if i.remaining <= 0 {
+		i.releaseIt()
+		return false
+	}
+	i.remaining--
+	return i.it.Next()
+}
+
+func (i *limited[T]) Value() T {
+	return i.it.Value()
+}
+
+func (i *limited[_]) Release() {
+	if i.guard.release() {
+		i.releaseIt()
+	}
+}
+
+func (i *limited[_]) releaseIt() {
+	if !i.itReleased {
+		i.itReleased = true
+		i.it.Release()
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []int
+		n        int
+		expected []int
+	}{
+		{
+			name:     "zero",
+			elements: []int{1, 2, 3},
+			n:        0,
+			expected: nil,
+		},
+		{
+			name:     "negative",
+			elements: []int{1, 2, 3},
+			n:        -1,
+			expected: nil,
+		},
+		{
+			name:     "less than length",
+			elements: []int{1, 2, 3},
+			n:        2,
+			expected: []int{1, 2},
+		},
+		{
+			name:     "equal to length",
+			elements: []int{1, 2, 3},
+			n:        3,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "greater than length",
+			elements: []int{1, 2, 3},
+			n:        5,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "empty",
+			elements: nil,
+			n:        5,
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			values, err := CollectErr(Limit(
+				FromSlice(test.elements...),
+				test.n,
+			))
+			require.NoError(err)
+			require.Equal(test.expected, values)
+		})
+	}
+}
+
+func TestLimitReleasesWhenReached(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := Limit[int](tracked, 2)
+
+	require.True(it.Next())
+	require.Equal(1, it.Value())
+	require.True(it.Next())
+	require.Equal(2, it.Value())
+	require.False(tracked.released)
+
+	require.False(it.Next())
+	require.True(tracked.released)
+
+	// Releasing the limited iterator must not release [tracked] again.
+	it.Release()
+}
+
+func TestLimitZeroReleases(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := Limit[int](tracked, 0)
+
+	require.False(it.Next())
+	require.True(tracked.released)
+	it.Release()
+}
+
+func TestLimitRelease(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := Limit[int](tracked, 2)
+
+	require.True(it.Next())
+	it.Release()
+	require.True(tracked.released)
+}
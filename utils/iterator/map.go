@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+var _ Iterator[any] = (*mapped[any, any])(nil)
+
+type mapped[T, U any] struct {
+	it     Iterator[T]
+	f      func(T) U
+	value  U
+	mapped bool
+	guard  releaseGuard
+}
+
+// Map returns an iterator that contains the result of applying [f] to each
+// element of [it]. [f] is applied lazily when Value is first called for an
+// element.
+func Map[T, U any](it Iterator[T], f func(T) U) Iterator[U] {
+	return &mapped[T, U]{
+		it: it,
+		f:  f,
+	}
+}
+
+func (i *mapped[_, U]) Next() bool {
+	var zero U
+	i.value = zero
+	i.mapped = false
+	return i.it.Next()
+}
+
+func (i *mapped[_, U]) Value() U {
+	if !i.mapped {
+		i.value = i.f(i.it.Value())
+		i.mapped = true
+	}
+	return i.value
+}
+
+func (i *mapped[_, _]) Release() {
+	if i.guard.release() {
+		i.it.Release()
+	}
+}
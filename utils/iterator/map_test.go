@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	require := require.New(t)
+
+	var calls []int
+	it := Map(
+		FromSlice(1, 2, 3),
+		func(i int) string {
+			calls = append(calls, i)
+			return strconv.Itoa(i)
+		},
+	)
+
+	// Skipping an element must not map it.
+	require.True(it.Next())
+
+	var values []string
+	for it.Next() {
+		values = append(values, it.Value())
+		// Repeated calls must not map the element again.
+		values = append(values, it.Value())
+	}
+	it.Release()
+
+	require.Equal([]string{"2", "2", "3", "3"}, values)
+	require.Equal([]int{2, 3}, calls)
+}
+
+func TestMapLength(t *testing.T) {
+	require := require.New(t)
+
+	source := []int{1, 2, 3, 4, 5}
+	it := Map(
+		FromSlice(source...),
+		func(i int) int {
+			return i * i
+		},
+	)
+	values, err := CollectErr(it)
+	require.NoError(err)
+	require.Len(values, len(source))
+}
+
+func TestMapFilter(t *testing.T) {
+	require := require.New(t)
+
+	it := Map(
+		Filter(
+			FromSlice(1, 2, 3, 4),
+			func(i int) bool {
+				return i%2 == 1
+			},
+		),
+		func(i int) string {
+			return strconv.Itoa(i)
+		},
+	)
+	values, err := CollectErr(it)
+	require.NoError(err)
+	require.Equal([]string{"2", "4"}, values)
+}
+
+func TestMapRelease(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := Map[int](
+		tracked,
+		func(i int) int {
+			return i
+		},
+	)
+	require.False(tracked.released)
+
+	it.Release()
+	require.True(tracked.released)
+}
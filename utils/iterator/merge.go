@@ -15,7 +15,8 @@ type merged[T any] struct {
 	initialized bool
 	// heap only contains iterators that have been initialized and are not
 	// exhausted.
-	heap heap.Queue[Iterator[T]]
+	heap  heap.Queue[Iterator[T]]
+	guard releaseGuard
 }
 
 // Merge returns an iterator that returns all of the elements of [iterators] in
@@ -82,6 +83,9 @@ func (it *merged[T]) Value() T {
 }
 
 func (it *merged[_]) Release() {
+	if !it.guard.release() {
+		return
+	}
 	for it.heap.Len() > 0 {
 		removed, _ := it.heap.Pop()
 		removed.Release()
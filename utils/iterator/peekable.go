@@ -0,0 +1,69 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+var _ Iterator[any] = (*Peekable[any])(nil)
+
+// Peekable is an iterator that allows inspecting the next element without
+// advancing.
+type Peekable[T any] struct {
+	it    Iterator[T]
+	value T
+
+	// peeked is true if [it] has been advanced past [value] by Peek.
+	peeked bool
+	// hasNext is the result of advancing [it] during Peek.
+	hasNext bool
+	next    T
+
+	guard releaseGuard
+}
+
+// NewPeekable returns [it] wrapped in a [Peekable] iterator.
+func NewPeekable[T any](it Iterator[T]) *Peekable[T] {
+	return &Peekable[T]{
+		it: it,
+	}
+}
+
+func (i *Peekable[_]) Next() bool {
+	if i.peeked {
+		i.peeked = false
+		if !i.hasNext {
+			return false
+		}
+		i.value = i.next
+		return true
+	}
+
+	if !i.it.Next() {
+		return false
+	}
+	i.value = i.it.Value()
+	return true
+}
+
+func (i *Peekable[T]) Value() T {
+	return i.value
+}
+
+// Peek returns the element that the next call to Next will advance to. If
+// there is no such element, false is returned. Repeated calls to Peek without
+// calling Next return the same element.
+func (i *Peekable[T]) Peek() (T, bool) {
+	if !i.peeked {
+		i.peeked = true
+		i.hasNext = i.it.Next()
+		if i.hasNext {
+			i.next = i.it.Value()
+		}
+	}
+	return i.next, i.hasNext
+}
+
+func (i *Peekable[_]) Release() {
+	if i.guard.release() {
+		i.it.Release()
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekable(t *testing.T) {
+	require := require.New(t)
+
+	it := NewPeekable(FromSlice(1, 2, 3, 4))
+
+	// Peeking before the first call to Next returns the first element.
+	next, ok := it.Peek()
+	require.True(ok)
+	require.Equal(1, next)
+
+	// Peek is idempotent.
+	next, ok = it.Peek()
+	require.True(ok)
+	require.Equal(1, next)
+
+	require.True(it.Next())
+	require.Equal(1, it.Value())
+
+	// Elements may be consumed without peeking.
+	require.True(it.Next())
+	require.Equal(2, it.Value())
+
+	// Peeking must not change the current value.
+	next, ok = it.Peek()
+	require.True(ok)
+	require.Equal(3, next)
+	require.Equal(2, it.Value())
+
+	require.True(it.Next())
+	require.Equal(3, it.Value())
+
+	require.True(it.Next())
+	require.Equal(4, it.Value())
+
+	_, ok = it.Peek()
+	require.False(ok)
+	_, ok = it.Peek()
+	require.False(ok)
+	require.Equal(4, it.Value())
+
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestPeekableMatchesSource(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		source = []int{1, 2, 3, 4, 5, 6, 7}
+		values []int
+	)
+	it := NewPeekable(FromSlice(source...))
+	for i := 0; it.Next(); i++ {
+		values = append(values, it.Value())
+		// Peek a varying number of times between calls to Next.
+		for j := 0; j < i%3; j++ {
+			_, _ = it.Peek()
+		}
+	}
+	it.Release()
+
+	require.Equal(source, values)
+}
+
+func TestPeekableEmpty(t *testing.T) {
+	require := require.New(t)
+
+	it := NewPeekable[int](Empty[int]{})
+	_, ok := it.Peek()
+	require.False(ok)
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestPeekableRelease(t *testing.T) {
+	require := require.New(t)
+
+	tracked := &trackedIterator[int]{Iterator: FromSlice(1, 2, 3)}
+	it := NewPeekable[int](tracked)
+	_, _ = it.Peek()
+	require.False(tracked.released)
+
+	it.Release()
+	require.True(tracked.released)
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+// Reduce folds the elements of [it] into an accumulator, starting from [init],
+// by applying [f] to each element in order. [it] is released once all of its
+// elements have been consumed.
+func Reduce[T, A any](it Iterator[T], init A, f func(A, T) A) A {
+	defer it.Release()
+
+	acc := init
+	for it.Next() {
+		acc = f(acc, it.Value())
+	}
+	return acc
+}
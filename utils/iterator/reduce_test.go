@@ -0,0 +1,31 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/iterator"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+)
+
+func TestReduce(t *testing.T) {
+	require := require.New(t)
+
+	sumWeights := func(total uint64, staker *state.Staker) uint64 {
+		return total + staker.Weight
+	}
+
+	require.Zero(iterator.Reduce(iterator.Empty[*state.Staker]{}, 0, sumWeights))
+
+	it := iterator.FromSlice(
+		&state.Staker{Weight: 1},
+		&state.Staker{Weight: 2},
+		&state.Staker{Weight: 3},
+	)
+	require.Equal(uint64(6), iterator.Reduce(it, 0, sumWeights))
+	require.Equal(uint64(16), iterator.Reduce(iterator.FromSlice(&state.Staker{Weight: 6}), 10, sumWeights))
+}
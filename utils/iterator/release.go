@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var errDoubleRelease = errors.New("iterator released more than once")
+
+// releaseGuard makes releasing an iterator idempotent. When built with the
+// debug tag, releasing an iterator more than once panics instead.
+type releaseGuard struct {
+	released atomic.Bool
+}
+
+// release returns true if this is the first call to release.
+func (g *releaseGuard) release() bool {
+	if g.released.CompareAndSwap(false, true) {
+		return true
+	}
+	if panicOnDoubleRelease {
+		panic(errDoubleRelease)
+	}
+	return false
+}
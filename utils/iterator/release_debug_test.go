@@ -0,0 +1,28 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build debug
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoubleReleasePanics(t *testing.T) {
+	for name, it := range newReleaseTestIterators() {
+		// Empty has no state with which to detect a double release.
+		if name == "empty" {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			it.Next()
+			require.NotPanics(it.Release)
+			require.PanicsWithValue(errDoubleRelease, it.Release)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+//go:build !debug
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoubleReleaseIsSafe(t *testing.T) {
+	for name, it := range newReleaseTestIterators() {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			it.Next()
+			it.Release()
+			require.NotPanics(it.Release)
+		})
+	}
+}
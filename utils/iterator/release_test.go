@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"cmp"
+	"context"
+
+	"github.com/google/btree"
+)
+
+func newReleaseTestIterators() map[string]Iterator[int] {
+	tree := btree.NewOrderedG[int](2)
+	for i := 0; i < 3; i++ {
+		tree.ReplaceOrInsert(i)
+	}
+	less := func(a, b int) bool {
+		return cmp.Less(a, b)
+	}
+	return map[string]Iterator[int]{
+		"empty": Empty[int]{},
+		"slice": FromSlice(1, 2, 3),
+		"tree":  FromTree(tree),
+		"filter": Filter(FromSlice(1, 2, 3), func(i int) bool {
+			return i%2 == 0
+		}),
+		"merge":    Merge(less, FromSlice(1, 3), FromSlice(2, 4)),
+		"limit":    Limit(FromSlice(1, 2, 3), 2),
+		"peekable": NewPeekable(FromSlice(1, 2, 3)),
+		"context":  WithContext(context.Background(), FromSlice(1, 2, 3)),
+		"zip": Map(Zip(FromSlice(1, 2), FromSlice(3, 4)), func(p Pair[int, int]) int {
+			return p.First + p.Second
+		}),
+		"map": Map(FromSlice(1, 2, 3), func(i int) int {
+			return i
+		}),
+	}
+}
+
+// trackedIterator records whether Release was called on the wrapped iterator.
+type trackedIterator[T any] struct {
+	Iterator[T]
+	released bool
+}
+
+func (t *trackedIterator[T]) Release() {
+	t.released = true
+	t.Iterator.Release()
+}
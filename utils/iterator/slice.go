@@ -8,6 +8,7 @@ var _ Iterator[any] = (*slice[any])(nil)
 type slice[T any] struct {
 	index    int
 	elements []T
+	guard    releaseGuard
 }
 
 // FromSlice returns an iterator that contains [elements] in order. Doesn't sort
@@ -28,4 +29,6 @@ func (i *slice[T]) Value() T {
 	return i.elements[i.index]
 }
 
-func (*slice[_]) Release() {}
+func (i *slice[_]) Release() {
+	i.guard.release()
+}
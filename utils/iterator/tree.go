@@ -12,11 +12,11 @@ import (
 var _ Iterator[any] = (*tree[any])(nil)
 
 type tree[T any] struct {
-	current     T
-	next        chan T
-	releaseOnce sync.Once
-	release     chan struct{}
-	wg          sync.WaitGroup
+	current T
+	next    chan T
+	guard   releaseGuard
+	release chan struct{}
+	wg      sync.WaitGroup
 }
 
 // FromTree returns a new iterator of the stakers in [tree] in ascending order.
@@ -25,6 +25,20 @@ func FromTree[T any](btree *btree.BTreeG[T]) Iterator[T] {
 	if btree == nil {
 		return Empty[T]{}
 	}
+	return newTree(btree.Ascend)
+}
+
+// FromTreeReverse returns a new iterator of the stakers in [tree] in descending
+// order. Note that it isn't safe to modify [tree] while iterating over it.
+func FromTreeReverse[T any](btree *btree.BTreeG[T]) Iterator[T] {
+	if btree == nil {
+		return Empty[T]{}
+	}
+	return newTree(btree.Descend)
+}
+
+// newTree returns an iterator over the elements visited by [walk].
+func newTree[T any](walk func(btree.ItemIteratorG[T])) *tree[T] {
 	it := &tree[T]{
 		next:    make(chan T),
 		release: make(chan struct{}),
@@ -32,7 +46,7 @@ func FromTree[T any](btree *btree.BTreeG[T]) Iterator[T] {
 	it.wg.Add(1)
 	go func() {
 		defer it.wg.Done()
-		btree.Ascend(func(i T) bool {
+		walk(func(i T) bool {
 			select {
 			case it.next <- i:
 				return true
@@ -56,8 +70,8 @@ func (i *tree[T]) Value() T {
 }
 
 func (i *tree[_]) Release() {
-	i.releaseOnce.Do(func() {
+	if i.guard.release() {
 		close(i.release)
-	})
+	}
 	i.wg.Wait()
 }
@@ -48,6 +48,43 @@ func TestTree(t *testing.T) {
 	it.Release()
 }
 
+func TestTreeReverse(t *testing.T) {
+	require := require.New(t)
+	stakers := []*state.Staker{
+		{
+			TxID:     ids.GenerateTestID(),
+			NextTime: time.Unix(0, 0),
+		},
+		{
+			TxID:     ids.GenerateTestID(),
+			NextTime: time.Unix(1, 0),
+		},
+		{
+			TxID:     ids.GenerateTestID(),
+			NextTime: time.Unix(2, 0),
+		},
+	}
+
+	tree := btree.NewG(defaultTreeDegree, (*state.Staker).Less)
+	for _, staker := range stakers {
+		require.Nil(tree.ReplaceOrInsert(staker))
+	}
+
+	it := iterator.FromTreeReverse(tree)
+	for i := len(stakers) - 1; i >= 0; i-- {
+		require.True(it.Next())
+		require.Equal(stakers[i], it.Value())
+	}
+	require.False(it.Next())
+	it.Release()
+}
+
+func TestTreeReverseNil(t *testing.T) {
+	it := iterator.FromTreeReverse[*state.Staker](nil)
+	require.False(t, it.Next())
+	it.Release()
+}
+
 func TestTreeNil(t *testing.T) {
 	it := iterator.FromTree[*state.Staker](nil)
 	require.False(t, it.Next())
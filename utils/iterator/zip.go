@@ -0,0 +1,45 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+var _ Iterator[Pair[any, any]] = (*zipped[any, any])(nil)
+
+// Pair contains the elements at the same position of two iterators.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+type zipped[A, B any] struct {
+	a     Iterator[A]
+	b     Iterator[B]
+	guard releaseGuard
+}
+
+// Zip returns an iterator that pairs up the elements of [a] and [b] in order.
+// Iteration stops once either [a] or [b] is exhausted.
+func Zip[A, B any](a Iterator[A], b Iterator[B]) Iterator[Pair[A, B]] {
+	return &zipped[A, B]{
+		a: a,
+		b: b,
+	}
+}
+
+func (i *zipped[_, _]) Next() bool {
+	return i.a.Next() && i.b.Next()
+}
+
+func (i *zipped[A, B]) Value() Pair[A, B] {
+	return Pair[A, B]{
+		First:  i.a.Value(),
+		Second: i.b.Value(),
+	}
+}
+
+func (i *zipped[_, _]) Release() {
+	if i.guard.release() {
+		i.a.Release()
+		i.b.Release()
+	}
+}
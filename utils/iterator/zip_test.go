@@ -0,0 +1,85 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestZip(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []int
+		b        []string
+		expected []Pair[int, string]
+	}{
+		{
+			name: "equal length",
+			a:    []int{1, 2},
+			b:    []string{"a", "b"},
+			expected: []Pair[int, string]{
+				{First: 1, Second: "a"},
+				{First: 2, Second: "b"},
+			},
+		},
+		{
+			name: "first shorter",
+			a:    []int{1},
+			b:    []string{"a", "b"},
+			expected: []Pair[int, string]{
+				{First: 1, Second: "a"},
+			},
+		},
+		{
+			name: "second shorter",
+			a:    []int{1, 2},
+			b:    []string{"a"},
+			expected: []Pair[int, string]{
+				{First: 1, Second: "a"},
+			},
+		},
+		{
+			name:     "first empty",
+			a:        nil,
+			b:        []string{"a"},
+			expected: nil,
+		},
+		{
+			name:     "both empty",
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			values, err := CollectErr(Zip(
+				FromSlice(test.a...),
+				FromSlice(test.b...),
+			))
+			require.NoError(err)
+			require.Equal(test.expected, values)
+		})
+	}
+}
+
+func TestZipRelease(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		a = &trackedIterator[int]{Iterator: FromSlice(1, 2)}
+		b = &trackedIterator[int]{Iterator: FromSlice(3)}
+	)
+	it := Zip[int, int](a, b)
+	require.True(it.Next())
+	require.False(it.Next())
+	require.False(a.released)
+	require.False(b.released)
+
+	it.Release()
+	require.True(a.released)
+	require.True(b.released)
+}
@@ -112,7 +112,8 @@ func newEnvironment(t *testing.T, f upgradetest.Fork) *environment { //nolint:un
 
 	res.fx = defaultFx(t, res.clk, res.ctx.Log, res.isBootstrapped.Get())
 
-	rewardsCalc := reward.NewCalculator(res.config.RewardConfig)
+	rewardsCalc, err := reward.NewCalculator(res.config.RewardConfig)
+	require.NoError(err)
 	res.state = statetest.New(t, statetest.Config{
 		DB:         res.baseDB,
 		Genesis:    genesistest.NewBytes(t, genesistest.Config{}),
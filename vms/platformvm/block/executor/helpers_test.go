@@ -116,7 +116,8 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller, f upgradetest.Fork) *
 
 	res.fx = defaultFx(res.clk, res.ctx.Log, res.isBootstrapped.Get())
 
-	rewardsCalc := reward.NewCalculator(res.config.RewardConfig)
+	rewardsCalc, err := reward.NewCalculator(res.config.RewardConfig)
+	require.NoError(t, err)
 
 	if ctrl == nil {
 		res.state = statetest.New(t, statetest.Config{
@@ -154,7 +155,6 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller, f upgradetest.Fork) *
 
 	metrics := metrics.Noop
 
-	var err error
 	res.mempool, err = mempool.New("mempool", registerer, nil)
 	if err != nil {
 		panic(fmt.Errorf("failed to create mempool: %w", err))
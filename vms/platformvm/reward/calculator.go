@@ -23,13 +23,18 @@ type calculator struct {
 	supplyCap                uint64
 }
 
-func NewCalculator(c Config) Calculator {
+// NewCalculator returns a Calculator that follows the reward curve described by
+// [c]. An error is returned if [c] is invalid.
+func NewCalculator(c Config) (Calculator, error) {
+	if err := c.Verify(); err != nil {
+		return nil, err
+	}
 	return &calculator{
 		maxSubMinConsumptionRate: new(big.Int).SetUint64(c.MaxConsumptionRate - c.MinConsumptionRate),
 		minConsumptionRate:       new(big.Int).SetUint64(c.MinConsumptionRate),
 		mintingPeriod:            new(big.Int).SetUint64(uint64(c.MintingPeriod)),
 		supplyCap:                c.SupplyCap,
-	}
+	}, nil
 }
 
 // Reward returns the amount of tokens to reward the staker with.
@@ -29,7 +29,8 @@ var defaultConfig = Config{
 }
 
 func TestLongerDurationBonus(t *testing.T) {
-	c := NewCalculator(defaultConfig)
+	c, err := NewCalculator(defaultConfig)
+	require.NoError(t, err)
 	shortDuration := 24 * time.Hour
 	totalDuration := 365 * 24 * time.Hour
 	shortBalance := units.KiloAvax
@@ -46,7 +47,8 @@ func TestLongerDurationBonus(t *testing.T) {
 }
 
 func TestRewards(t *testing.T) {
-	c := NewCalculator(defaultConfig)
+	c, err := NewCalculator(defaultConfig)
+	require.NoError(t, err)
 	tests := []struct {
 		duration       time.Duration
 		stakeAmount    uint64
@@ -138,12 +140,13 @@ func TestRewardsOverflow(t *testing.T) {
 		maxSupply     uint64 = math.MaxUint64
 		initialSupply uint64 = 1
 	)
-	c := NewCalculator(Config{
+	c, err := NewCalculator(Config{
 		MaxConsumptionRate: PercentDenominator,
 		MinConsumptionRate: PercentDenominator,
 		MintingPeriod:      defaultMinStakingDuration,
 		SupplyCap:          maxSupply,
 	})
+	require.NoError(t, err)
 	reward := c.Calculate(
 		defaultMinStakingDuration,
 		maxSupply, // The staked amount is larger than the current supply
@@ -157,12 +160,13 @@ func TestRewardsMint(t *testing.T) {
 		maxSupply     uint64 = 1000
 		initialSupply uint64 = 1
 	)
-	c := NewCalculator(Config{
+	c, err := NewCalculator(Config{
 		MaxConsumptionRate: PercentDenominator,
 		MinConsumptionRate: PercentDenominator,
 		MintingPeriod:      defaultMinStakingDuration,
 		SupplyCap:          maxSupply,
 	})
+	require.NoError(t, err)
 	rewards := c.Calculate(
 		defaultMinStakingDuration,
 		maxSupply, // The staked amount is larger than the current supply
@@ -171,6 +175,135 @@ func TestRewardsMint(t *testing.T) {
 	require.Equal(t, maxSupply-initialSupply, rewards)
 }
 
+func TestCustomCurveRewards(t *testing.T) {
+	const mintingPeriod = 100 * 24 * time.Hour
+	c, err := NewCalculator(Config{
+		MaxConsumptionRate: .20 * PercentDenominator,
+		MinConsumptionRate: .05 * PercentDenominator,
+		MintingPeriod:      mintingPeriod,
+		SupplyCap:          1000 * units.MegaAvax,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		duration       time.Duration
+		stakeAmount    uint64
+		existingAmount uint64
+		expectedReward uint64
+	}{
+		{ // (1000M - 500M) * (1M / 500M) * 20% * 1
+			duration:       mintingPeriod,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 500 * units.MegaAvax,
+			expectedReward: 200 * units.KiloAvax,
+		},
+		{ // (1000M - 500M) * (1M / 500M) * (5% + 15% * 1/2) * 1/2
+			duration:       mintingPeriod / 2,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 500 * units.MegaAvax,
+			expectedReward: 62_500 * units.Avax,
+		},
+		{ // (1000M - 500M) * (1M / 500M) * (5% + 15% * 1/4) * 1/4
+			duration:       mintingPeriod / 4,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 500 * units.MegaAvax,
+			expectedReward: 21_875 * units.Avax,
+		},
+		{ // (1000M - 500M) * (1M / 500M) * (5% + 15% * 1/10) * 1/10
+			duration:       mintingPeriod / 10,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 500 * units.MegaAvax,
+			expectedReward: 6_500 * units.Avax,
+		},
+		{ // (1000M - 500M) * (1M / 500M) * 5% * 0
+			duration:       0,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 500 * units.MegaAvax,
+			expectedReward: 0,
+		},
+		{ // (1000M - 999M) * (1M / 999M) * 20% * 1, rounded down
+			duration:       mintingPeriod,
+			stakeAmount:    units.MegaAvax,
+			existingAmount: 999 * units.MegaAvax,
+			expectedReward: 200_200_200_200,
+		},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("reward(%s,%d,%d)==%d",
+			test.duration,
+			test.stakeAmount,
+			test.existingAmount,
+			test.expectedReward,
+		)
+		t.Run(name, func(t *testing.T) {
+			reward := c.Calculate(
+				test.duration,
+				test.stakeAmount,
+				test.existingAmount,
+			)
+			require.Equal(t, test.expectedReward, reward)
+		})
+	}
+}
+
+func TestNewCalculatorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedErr error
+	}{
+		{
+			name: "min consumption rate above max",
+			config: Config{
+				MaxConsumptionRate: .10 * PercentDenominator,
+				MinConsumptionRate: .12 * PercentDenominator,
+				MintingPeriod:      defaultMaxStakingDuration,
+				SupplyCap:          720 * units.MegaAvax,
+			},
+			expectedErr: errMinConsumptionRateAboveMax,
+		},
+		{
+			name: "zero minting period",
+			config: Config{
+				MaxConsumptionRate: .12 * PercentDenominator,
+				MinConsumptionRate: .10 * PercentDenominator,
+				SupplyCap:          720 * units.MegaAvax,
+			},
+			expectedErr: errZeroMintingPeriod,
+		},
+		{
+			name: "zero supply cap",
+			config: Config{
+				MaxConsumptionRate: .12 * PercentDenominator,
+				MinConsumptionRate: .10 * PercentDenominator,
+				MintingPeriod:      defaultMaxStakingDuration,
+			},
+			expectedErr: errZeroSupplyCap,
+		},
+		{
+			name: "equal consumption rates",
+			config: Config{
+				MaxConsumptionRate: .10 * PercentDenominator,
+				MinConsumptionRate: .10 * PercentDenominator,
+				MintingPeriod:      defaultMaxStakingDuration,
+				SupplyCap:          720 * units.MegaAvax,
+			},
+			expectedErr: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			c, err := NewCalculator(test.config)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				require.Nil(c)
+			}
+		})
+	}
+}
+
 func TestSplit(t *testing.T) {
 	tests := []struct {
 		amount        uint64
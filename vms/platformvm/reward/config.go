@@ -4,6 +4,7 @@
 package reward
 
 import (
+	"errors"
 	"math/big"
 	"time"
 )
@@ -11,6 +12,12 @@ import (
 // PercentDenominator is the denominator used to calculate percentages
 const PercentDenominator = 1_000_000
 
+var (
+	errMinConsumptionRateAboveMax = errors.New("min consumption rate must be less than or equal to max consumption rate")
+	errZeroMintingPeriod          = errors.New("minting period must be non-zero")
+	errZeroSupplyCap              = errors.New("supply cap must be non-zero")
+)
+
 // consumptionRateDenominator is the magnitude offset used to emulate
 // floating point fractions.
 var consumptionRateDenominator = new(big.Int).SetUint64(PercentDenominator)
@@ -32,3 +39,17 @@ type Config struct {
 	// asymptotic to.
 	SupplyCap uint64 `json:"supplyCap"`
 }
+
+// Verify returns an error if the reward curve described by [c] is invalid.
+func (c *Config) Verify() error {
+	switch {
+	case c.MinConsumptionRate > c.MaxConsumptionRate:
+		return errMinConsumptionRateAboveMax
+	case c.MintingPeriod <= 0:
+		return errZeroMintingPeriod
+	case c.SupplyCap == 0:
+		return errZeroSupplyCap
+	default:
+		return nil
+	}
+}
@@ -28,7 +28,7 @@ func ExampleNewCalculator() {
 			MintingPeriod:      365 * 24 * time.Hour,
 			SupplyCap:          720 * units.MegaAvax,
 		}
-		mainnetCalculator = NewCalculator(mainnetRewardConfig)
+		mainnetCalculator, _ = NewCalculator(mainnetRewardConfig)
 	)
 
 	potentialReward := mainnetCalculator.Calculate(stakingDuration, stakeAmount, currentSupply)
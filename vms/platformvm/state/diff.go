@@ -202,6 +202,17 @@ func (d *diff) GetCurrentDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID) (
 	return d.currentStakerDiffs.GetDelegatorIterator(parentIterator, subnetID, nodeID), nil
 }
 
+// NumDelegators returns the number of current delegators of the validator on
+// [subnetID] with [nodeID]. If the parent state is missing, only the
+// delegators added by this diff are counted.
+func (d *diff) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	var parentNumDelegators int
+	if parentState, ok := d.stateVersions.GetState(d.parentID); ok {
+		parentNumDelegators = parentState.NumDelegators(subnetID, nodeID)
+	}
+	return d.currentStakerDiffs.NumDelegators(parentNumDelegators, subnetID, nodeID)
+}
+
 func (d *diff) PutCurrentDelegator(staker *Staker) {
 	d.currentStakerDiffs.PutDelegator(staker)
 }
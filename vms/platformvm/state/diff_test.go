@@ -161,6 +161,32 @@ func TestDiffPendingValidator(t *testing.T) {
 	require.ErrorIs(err, database.ErrNotFound)
 }
 
+func TestDiffNumDelegators(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	delegator := &Staker{
+		TxID:     ids.GenerateTestID(),
+		SubnetID: ids.GenerateTestID(),
+		NodeID:   ids.GenerateTestNodeID(),
+	}
+
+	state := NewMockState(ctrl)
+	// Called in NewDiffOn
+	state.EXPECT().GetTimestamp().Return(time.Now()).Times(1)
+	state.EXPECT().GetFeeState().Return(gas.State{}).Times(1)
+
+	d, err := NewDiffOn(state)
+	require.NoError(err)
+
+	state.EXPECT().NumDelegators(delegator.SubnetID, delegator.NodeID).Return(2).Times(2)
+	d.PutCurrentDelegator(delegator)
+	require.Equal(3, d.NumDelegators(delegator.SubnetID, delegator.NodeID))
+
+	d.DeleteCurrentDelegator(delegator)
+	require.Equal(2, d.NumDelegators(delegator.SubnetID, delegator.NodeID))
+}
+
 func TestDiffCurrentDelegator(t *testing.T) {
 	require := require.New(t)
 	ctrl := gomock.NewController(t)
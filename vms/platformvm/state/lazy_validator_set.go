@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import "github.com/ava-labs/avalanchego/ids"
+
+// LazyValidatorSet provides access to the validators of a subnet without
+// building a map of all of them unless explicitly requested. All lookups are
+// performed against the live staker set, so modifications to the staker set
+// are immediately visible.
+type LazyValidatorSet struct {
+	stakers  *baseStakers
+	subnetID ids.ID
+}
+
+// LazyValidators returns the validators of [subnetID] as a [LazyValidatorSet].
+func (v *baseStakers) LazyValidators(subnetID ids.ID) *LazyValidatorSet {
+	return &LazyValidatorSet{
+		stakers:  v,
+		subnetID: subnetID,
+	}
+}
+
+// Get returns the validator with [nodeID], if it exists.
+func (s *LazyValidatorSet) Get(nodeID ids.NodeID) (*Staker, bool) {
+	validator, ok := s.stakers.validators[s.subnetID][nodeID]
+	if !ok || validator.validator == nil {
+		return nil, false
+	}
+	return validator.validator, true
+}
+
+// Len returns the number of validators.
+func (s *LazyValidatorSet) Len() int {
+	if counts, ok := s.stakers.subnetCounts[s.subnetID]; ok {
+		return counts.validators
+	}
+	return 0
+}
+
+// All returns a newly allocated map of every validator, keyed by NodeID.
+func (s *LazyValidatorSet) All() map[ids.NodeID]*Staker {
+	validators := make(map[ids.NodeID]*Staker, s.Len())
+	for nodeID, validator := range s.stakers.validators[s.subnetID] {
+		if validator.validator != nil {
+			validators[nodeID] = validator.validator
+		}
+	}
+	return validators
+}
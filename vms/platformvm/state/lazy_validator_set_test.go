@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestLazyValidatorSet(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	s := v.LazyValidators(subnetID)
+	require.Zero(s.Len())
+	require.Empty(s.All())
+
+	var (
+		validator = newTestValidator(subnetID, 1)
+		other     = newTestValidator(subnetID, 2)
+		delegator = newTestStaker()
+	)
+	v.PutValidator(validator)
+	v.PutValidator(other)
+
+	// A delegator without a validator must not be returned.
+	delegator.SubnetID = subnetID
+	v.PutDelegator(delegator)
+
+	// Validators on other subnets must not be returned.
+	v.PutValidator(newTestStaker())
+
+	got, ok := s.Get(validator.NodeID)
+	require.True(ok)
+	require.Equal(validator, got)
+
+	_, ok = s.Get(delegator.NodeID)
+	require.False(ok)
+
+	// Individual lookups must not materialize the validator set.
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = s.Get(validator.NodeID)
+		_, _ = s.Get(delegator.NodeID)
+		_ = s.Len()
+	})
+	require.Zero(allocs)
+
+	require.Equal(2, s.Len())
+	require.Equal(
+		map[ids.NodeID]*Staker{
+			validator.NodeID: validator,
+			other.NodeID:     other,
+		},
+		s.All(),
+	)
+
+	// Modifications to the staker set must be visible.
+	v.DeleteValidator(other)
+
+	_, ok = s.Get(other.NodeID)
+	require.False(ok)
+	require.Equal(
+		map[ids.NodeID]*Staker{
+			validator.NodeID: validator,
+		},
+		s.All(),
+	)
+}
@@ -403,6 +403,20 @@ func (mr *MockChainMockRecorder) GetUTXO(utxoID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockChain)(nil).GetUTXO), utxoID)
 }
 
+// NumDelegators mocks base method.
+func (m *MockChain) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumDelegators", subnetID, nodeID)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NumDelegators indicates an expected call of NumDelegators.
+func (mr *MockChainMockRecorder) NumDelegators(subnetID, nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumDelegators", reflect.TypeOf((*MockChain)(nil).NumDelegators), subnetID, nodeID)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockChain) PutCurrentDelegator(staker *Staker) {
 	m.ctrl.T.Helper()
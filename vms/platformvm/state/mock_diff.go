@@ -417,6 +417,20 @@ func (mr *MockDiffMockRecorder) GetUTXO(utxoID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUTXO", reflect.TypeOf((*MockDiff)(nil).GetUTXO), utxoID)
 }
 
+// NumDelegators mocks base method.
+func (m *MockDiff) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumDelegators", subnetID, nodeID)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NumDelegators indicates an expected call of NumDelegators.
+func (mr *MockDiffMockRecorder) NumDelegators(subnetID, nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumDelegators", reflect.TypeOf((*MockDiff)(nil).NumDelegators), subnetID, nodeID)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockDiff) PutCurrentDelegator(staker *Staker) {
 	m.ctrl.T.Helper()
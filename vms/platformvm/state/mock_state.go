@@ -638,6 +638,20 @@ func (mr *MockStateMockRecorder) GetUptime(nodeID, subnetID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUptime", reflect.TypeOf((*MockState)(nil).GetUptime), nodeID, subnetID)
 }
 
+// NumDelegators mocks base method.
+func (m *MockState) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NumDelegators", subnetID, nodeID)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// NumDelegators indicates an expected call of NumDelegators.
+func (mr *MockStateMockRecorder) NumDelegators(subnetID, nodeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NumDelegators", reflect.TypeOf((*MockState)(nil).NumDelegators), subnetID, nodeID)
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockState) PutCurrentDelegator(staker *Staker) {
 	m.ctrl.T.Helper()
@@ -5,6 +5,10 @@ package state
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"time"
 
 	"github.com/google/btree"
@@ -12,9 +16,17 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
-var _ btree.LessFunc[*Staker] = (*Staker).Less
+const year = 365 * 24 * time.Hour
+
+var (
+	_ btree.LessFunc[*Staker] = (*Staker).Less
+
+	errNegativeRate = errors.New("negative rate")
+)
 
 // Staker contains all information required to represent a validator or
 // delegator in the current and pending validator sets.
@@ -41,6 +53,10 @@ type Staker struct {
 	// [priorities.go] and depends on if the stakers are in the pending or
 	// current validator set.
 	Priority txs.Priority
+
+	// Slashed is true if this staker has been marked as slashed. It is not
+	// persisted and does not modify the staker's rewards.
+	Slashed bool
 }
 
 // A *Staker is considered to be less than another *Staker when:
@@ -68,6 +84,77 @@ func (s *Staker) Less(than *Staker) bool {
 	return bytes.Compare(s.TxID[:], than.TxID[:]) == -1
 }
 
+// EarnedRewardSoFar returns the portion of the staker's potential reward that
+// has accrued by [now], assuming the reward accrues linearly over
+// [StartTime, EndTime]. The full potential reward is returned once [now]
+// reaches EndTime.
+func (s *Staker) EarnedRewardSoFar(now time.Time) uint64 {
+	if !now.Before(s.EndTime) {
+		return s.PotentialReward
+	}
+	if !now.After(s.StartTime) {
+		return 0
+	}
+
+	// The intermediate product may overflow a uint64.
+	var (
+		elapsed = now.Sub(s.StartTime)
+		period  = s.EndTime.Sub(s.StartTime)
+		earned  = new(big.Int).SetUint64(s.PotentialReward)
+	)
+	earned.Mul(earned, big.NewInt(int64(elapsed)))
+	earned.Div(earned, big.NewInt(int64(period)))
+	return earned.Uint64()
+}
+
+// RewardPerWeight returns the staker's potential reward per unit of weight.
+func (s *Staker) RewardPerWeight() (float64, error) {
+	if s.Weight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, s.TxID)
+	}
+	return float64(s.PotentialReward) / float64(s.Weight), nil
+}
+
+// APY returns the annual percentage yield of the staker's potential reward as
+// a fraction of its weight, assuming the reward is compounded once per staking
+// period of [StartTime, EndTime].
+func (s *Staker) APY() (float64, error) {
+	if s.Weight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, s.TxID)
+	}
+	duration := s.EndTime.Sub(s.StartTime)
+	if duration <= 0 {
+		return 0, fmt.Errorf("%w: %s", errInvalidTimeWindow, s.TxID)
+	}
+
+	var (
+		periodYield    = float64(s.PotentialReward) / float64(s.Weight)
+		periodsPerYear = float64(year) / float64(duration)
+	)
+	return math.Pow(1+periodYield, periodsPerYear) - 1, nil
+}
+
+// RewardIfExtendedTo returns the reward the staker would be eligible for if it
+// staked from StartTime until [newEndTime], accruing [rate] of its weight per
+// year. [newEndTime] must not be before EndTime.
+func (s *Staker) RewardIfExtendedTo(newEndTime time.Time, rate float64) (uint64, error) {
+	if newEndTime.Before(s.EndTime) {
+		return 0, fmt.Errorf("%w: %s is before %s", errInvalidTimeWindow, newEndTime, s.EndTime)
+	}
+	if rate < 0 {
+		return 0, fmt.Errorf("%w: %f", errNegativeRate, rate)
+	}
+
+	var (
+		duration = newEndTime.Sub(s.StartTime)
+		reward   = float64(s.Weight) * rate * float64(duration) / float64(year)
+	)
+	if reward >= math.MaxUint64 {
+		return 0, safemath.ErrOverflow
+	}
+	return uint64(reward), nil
+}
+
 func NewCurrentStaker(
 	txID ids.ID,
 	staker txs.Staker,
@@ -0,0 +1,86 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// SchemaVersion is the version used to serialize a [Staker] in a state diff.
+// It is the only version of the staker schema.
+const SchemaVersion = CodecVersion0
+
+var errUnexpectedSchemaVersion = errors.New("unexpected staker schema version")
+
+type stakerSchema struct {
+	TxID            ids.ID       `v0:"true"`
+	NodeID          ids.NodeID   `v0:"true"`
+	SubnetID        ids.ID       `v0:"true"`
+	Weight          uint64       `v0:"true"`
+	StartTime       uint64       `v0:"true"` // Unix time in seconds
+	EndTime         uint64       `v0:"true"` // Unix time in seconds
+	PotentialReward uint64       `v0:"true"`
+	NextTime        uint64       `v0:"true"` // Unix time in seconds
+	Priority        txs.Priority `v0:"true"`
+	PublicKey       []byte       `v0:"true"` // Compressed, empty if unset
+}
+
+func newStakerSchema(staker *Staker) stakerSchema {
+	schema := stakerSchema{
+		TxID:            staker.TxID,
+		NodeID:          staker.NodeID,
+		SubnetID:        staker.SubnetID,
+		Weight:          staker.Weight,
+		StartTime:       uint64(staker.StartTime.Unix()),
+		EndTime:         uint64(staker.EndTime.Unix()),
+		PotentialReward: staker.PotentialReward,
+		NextTime:        uint64(staker.NextTime.Unix()),
+		Priority:        staker.Priority,
+	}
+	if staker.PublicKey != nil {
+		schema.PublicKey = bls.PublicKeyToCompressedBytes(staker.PublicKey)
+	}
+	return schema
+}
+
+func (s *stakerSchema) staker() (*Staker, error) {
+	staker := &Staker{
+		TxID:            s.TxID,
+		NodeID:          s.NodeID,
+		SubnetID:        s.SubnetID,
+		Weight:          s.Weight,
+		StartTime:       time.Unix(int64(s.StartTime), 0),
+		EndTime:         time.Unix(int64(s.EndTime), 0),
+		PotentialReward: s.PotentialReward,
+		NextTime:        time.Unix(int64(s.NextTime), 0),
+		Priority:        s.Priority,
+	}
+	if len(s.PublicKey) != 0 {
+		var err error
+		staker.PublicKey, err = bls.PublicKeyFromCompressedBytes(s.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return staker, nil
+}
+
+func (s *stakerSchema) equal(other *stakerSchema) bool {
+	return s.TxID == other.TxID &&
+		s.NodeID == other.NodeID &&
+		s.SubnetID == other.SubnetID &&
+		s.Weight == other.Weight &&
+		s.StartTime == other.StartTime &&
+		s.EndTime == other.EndTime &&
+		s.PotentialReward == other.PotentialReward &&
+		s.NextTime == other.NextTime &&
+		s.Priority == other.Priority &&
+		bytes.Equal(s.PublicKey, other.PublicKey)
+}
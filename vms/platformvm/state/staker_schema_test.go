@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+func TestStakerSchemaRoundTrip(t *testing.T) {
+	sk, err := bls.NewSecretKey()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		publicKey *bls.PublicKey
+	}{
+		{
+			name: "without public key",
+		},
+		{
+			name:      "with public key",
+			publicKey: bls.PublicFromSecretKey(sk),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			staker := &Staker{
+				TxID:            ids.GenerateTestID(),
+				NodeID:          ids.GenerateTestNodeID(),
+				PublicKey:       test.publicKey,
+				SubnetID:        ids.GenerateTestID(),
+				Weight:          1,
+				StartTime:       time.Unix(1, 0),
+				EndTime:         time.Unix(3, 0),
+				PotentialReward: 2,
+				NextTime:        time.Unix(3, 0),
+				Priority:        txs.PrimaryNetworkValidatorCurrentPriority,
+			}
+
+			schema := newStakerSchema(staker)
+			schemaBytes, err := MetadataCodec.Marshal(SchemaVersion, &schema)
+			require.NoError(err)
+
+			var parsedSchema stakerSchema
+			version, err := MetadataCodec.Unmarshal(schemaBytes, &parsedSchema)
+			require.NoError(err)
+			require.Equal(SchemaVersion, version)
+			require.True(parsedSchema.equal(&schema))
+
+			parsedStaker, err := parsedSchema.staker()
+			require.NoError(err)
+			require.True(stakersEqual(staker, parsedStaker))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package state
 
 import (
 	"errors"
+	"math"
 	"testing"
 	"time"
 
@@ -13,9 +14,12 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
 	"github.com/ava-labs/avalanchego/vms/platformvm/signer/signermock"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
 var errCustom = errors.New("custom")
@@ -200,6 +204,268 @@ func TestNewPendingStaker(t *testing.T) {
 	require.ErrorIs(err, errCustom)
 }
 
+func TestStakerEarnedRewardSoFar(t *testing.T) {
+	var (
+		startTime = time.Unix(0, 0)
+		endTime   = time.Unix(1000, 0)
+		staker    = Staker{
+			StartTime:       startTime,
+			EndTime:         endTime,
+			PotentialReward: 1000,
+		}
+	)
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		expected uint64
+	}{
+		{
+			name:     "before start",
+			now:      startTime.Add(-time.Second),
+			expected: 0,
+		},
+		{
+			name:     "at start",
+			now:      startTime,
+			expected: 0,
+		},
+		{
+			name:     "at midpoint",
+			now:      time.Unix(500, 0),
+			expected: 500,
+		},
+		{
+			name:     "at end",
+			now:      endTime,
+			expected: 1000,
+		},
+		{
+			name:     "after end",
+			now:      endTime.Add(time.Hour),
+			expected: 1000,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, staker.EarnedRewardSoFar(test.now))
+		})
+	}
+}
+
+func TestStakerEarnedRewardSoFarLargeReward(t *testing.T) {
+	staker := Staker{
+		StartTime:       time.Unix(0, 0),
+		EndTime:         time.Unix(0, 0).Add(365 * 24 * time.Hour),
+		PotentialReward: math.MaxUint64,
+	}
+	require.Equal(t, uint64(math.MaxUint64/2), staker.EarnedRewardSoFar(time.Unix(0, 0).Add(365*12*time.Hour)))
+}
+
+func TestStakerRewardPerWeight(t *testing.T) {
+	tests := []struct {
+		name        string
+		weight      uint64
+		reward      uint64
+		expected    float64
+		expectedErr error
+	}{
+		{
+			name:        "zero weight",
+			weight:      0,
+			reward:      100,
+			expectedErr: errZeroWeight,
+		},
+		{
+			name:     "zero reward",
+			weight:   100,
+			reward:   0,
+			expected: 0,
+		},
+		{
+			name:     "reward equals weight",
+			weight:   100,
+			reward:   100,
+			expected: 1,
+		},
+		{
+			name:     "fractional",
+			weight:   2000,
+			reward:   50,
+			expected: 0.025,
+		},
+		{
+			name:     "large values",
+			weight:   math.MaxUint64,
+			reward:   math.MaxUint64 / 2,
+			expected: 0.5,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			staker := Staker{
+				Weight:          test.weight,
+				PotentialReward: test.reward,
+			}
+			rewardPerWeight, err := staker.RewardPerWeight()
+			require.ErrorIs(err, test.expectedErr)
+			require.InDelta(test.expected, rewardPerWeight, floatDelta)
+		})
+	}
+}
+
+func TestStakerAPY(t *testing.T) {
+	startTime := time.Unix(0, 0)
+	tests := []struct {
+		name        string
+		weight      uint64
+		reward      uint64
+		duration    time.Duration
+		expected    float64
+		expectedErr error
+	}{
+		{
+			name:        "zero weight",
+			weight:      0,
+			reward:      100,
+			duration:    year,
+			expectedErr: errZeroWeight,
+		},
+		{
+			name:        "zero duration",
+			weight:      100,
+			reward:      100,
+			duration:    0,
+			expectedErr: errInvalidTimeWindow,
+		},
+		{
+			name:     "zero reward",
+			weight:   2_000 * units.Avax,
+			reward:   0,
+			duration: year,
+			expected: 0,
+		},
+		{
+			name:     "one year",
+			weight:   2_000 * units.Avax,
+			reward:   160 * units.Avax,
+			duration: year,
+			expected: 0.08,
+		},
+		{
+			name:     "half year",
+			weight:   2_000 * units.Avax,
+			reward:   80 * units.Avax,
+			duration: year / 2,
+			expected: 0.0816, // 1.04^2 - 1
+		},
+		{
+			name:     "two weeks",
+			weight:   25 * units.Avax,
+			reward:   units.Avax / 20,
+			duration: 14 * 24 * time.Hour,
+			expected: 0.053471, // 1.002^(365/14) - 1
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			staker := Staker{
+				Weight:          test.weight,
+				StartTime:       startTime,
+				EndTime:         startTime.Add(test.duration),
+				PotentialReward: test.reward,
+			}
+			apy, err := staker.APY()
+			require.ErrorIs(err, test.expectedErr)
+			require.InDelta(test.expected, apy, floatDelta)
+		})
+	}
+}
+
+func TestStakerRewardIfExtendedTo(t *testing.T) {
+	var (
+		startTime = time.Unix(0, 0)
+		endTime   = startTime.Add(year / 2)
+		staker    = Staker{
+			Weight:    2_000 * units.Avax,
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	)
+
+	tests := []struct {
+		name        string
+		newEndTime  time.Time
+		rate        float64
+		expected    uint64
+		expectedErr error
+	}{
+		{
+			name:        "earlier end time",
+			newEndTime:  endTime.Add(-time.Second),
+			rate:        0.08,
+			expectedErr: errInvalidTimeWindow,
+		},
+		{
+			name:        "negative rate",
+			newEndTime:  endTime,
+			rate:        -0.08,
+			expectedErr: errNegativeRate,
+		},
+		{
+			name:       "unchanged end time",
+			newEndTime: endTime,
+			rate:       0.08,
+			expected:   80 * units.Avax,
+		},
+		{
+			name:       "extended to a year",
+			newEndTime: startTime.Add(year),
+			rate:       0.08,
+			expected:   160 * units.Avax,
+		},
+		{
+			name:       "extended to two years",
+			newEndTime: startTime.Add(2 * year),
+			rate:       0.08,
+			expected:   320 * units.Avax,
+		},
+		{
+			name:       "zero rate",
+			newEndTime: startTime.Add(year),
+			rate:       0,
+			expected:   0,
+		},
+		{
+			name:        "overflow",
+			newEndTime:  startTime.Add(year),
+			rate:        math.MaxFloat64,
+			expectedErr: safemath.ErrOverflow,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			reward, err := staker.RewardIfExtendedTo(test.newEndTime, test.rate)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expected, reward)
+		})
+	}
+
+	// Extending the staking period must never reduce the reward.
+	require := require.New(t)
+	original, err := staker.RewardIfExtendedTo(endTime, 0.08)
+	require.NoError(err)
+	extended, err := staker.RewardIfExtendedTo(endTime.Add(time.Hour), 0.08)
+	require.NoError(err)
+	require.Greater(extended, original)
+}
+
 func generateStakerTx(require *require.Assertions) *txs.AddPermissionlessValidatorTx {
 	nodeID := ids.GenerateTestNodeID()
 	sk, err := bls.NewSecretKey()
@@ -4,20 +4,50 @@
 package state
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/bits"
+	"slices"
+	"time"
 
 	"github.com/google/btree"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/iterator"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
-var ErrAddingStakerAfterDeletion = errors.New("attempted to add a staker after deleting it")
+var (
+	ErrAddingStakerAfterDeletion = errors.New("attempted to add a staker after deleting it")
+
+	errNoValidators      = errors.New("no validators")
+	errZeroWeight        = errors.New("zero weight")
+	errInvalidTimeWindow = errors.New("invalid time window")
+	errTotalsMismatch    = errors.New("totals mismatch")
+	errInvalidQuantile   = errors.New("invalid quantile")
+	errZeroSupply        = errors.New("zero supply")
+	errInvalidDiffIndex  = errors.New("invalid diff index")
+	errUnreachableStake  = errors.New("unreachable stake threshold")
+)
 
 type Stakers interface {
 	CurrentStakers
 	PendingStakers
+
+	// NumDelegators returns the number of current delegators of the validator
+	// on [subnetID] with [nodeID]. If the validator is unknown, 0 is returned.
+	NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int
 }
 
 type CurrentStakers interface {
@@ -106,105 +136,1727 @@ type baseStakers struct {
 	stakers    *btree.BTreeG[*Staker]
 	// subnetID --> nodeID --> diff for that validator since the last db write
 	validatorDiffs map[ids.ID]map[ids.NodeID]*diffValidator
+	// totals are maintained incrementally as validators and delegators are
+	// added and removed.
+	totals stakerTotals
+	// subnetID --> validators of the subnet ordered by weight
+	validatorsByWeight map[ids.ID]*btree.BTreeG[*Staker]
+	// subnetID --> number of stakers on the subnet
+	subnetCounts map[ids.ID]*subnetStakerCounts
+	// subnetID --> time of the most recent staker addition or removal on the
+	// subnet. Only subnets with stakers are tracked.
+	lastActivity map[ids.ID]time.Time
+	// timestamp is the chain time at which additions and removals are
+	// recorded as activity.
+	timestamp time.Time
+	// metrics is optional and is not copied by Clone.
+	metrics *stakerMetrics
+}
+
+type subnetStakerCounts struct {
+	validators      int
+	delegators      int
+	potentialReward rewardSum
+}
+
+// rewardSum is a sum of potential rewards. The sum is maintained with 128 bits
+// so that it remains exact even if it temporarily exceeds a uint64.
+type rewardSum struct {
+	hi, lo uint64
+}
+
+func (s *rewardSum) add(reward uint64) {
+	var carry uint64
+	s.lo, carry = bits.Add64(s.lo, reward, 0)
+	s.hi += carry
+}
+
+func (s *rewardSum) sub(reward uint64) {
+	var borrow uint64
+	s.lo, borrow = bits.Sub64(s.lo, reward, 0)
+	s.hi -= borrow
+}
+
+// value returns the sum, or [safemath.ErrOverflow] if it exceeds a uint64.
+func (s rewardSum) value() (uint64, error) {
+	if s.hi != 0 {
+		return 0, safemath.ErrOverflow
+	}
+	return s.lo, nil
+}
+
+type baseStaker struct {
+	validator  *Staker
+	delegators *btree.BTreeG[*Staker]
+}
+
+// numDelegators returns the number of delegators of [s].
+func (s *baseStaker) numDelegators() int {
+	if s.delegators == nil {
+		return 0
+	}
+	return s.delegators.Len()
+}
+
+// delegatorWeight returns the total weight of the delegators of [s].
+func (s *baseStaker) delegatorWeight() (uint64, error) {
+	if s.delegators == nil {
+		return 0, nil
+	}
+	var (
+		weight uint64
+		err    error
+	)
+	s.delegators.Ascend(func(delegator *Staker) bool {
+		weight, err = safemath.Add(weight, delegator.Weight)
+		return err == nil
+	})
+	return weight, err
+}
+
+// stakerTotals are aggregates over the validators and delegators of a staker
+// set.
+type stakerTotals struct {
+	numValidators   int
+	numDelegators   int
+	validatorWeight uint64
+	delegatorWeight uint64
+	potentialReward uint64
+	// overflowed is set if any of the sums overflowed or underflowed, in which
+	// case the sums are no longer meaningful.
+	overflowed bool
+}
+
+func (t *stakerTotals) addValidator(staker *Staker) {
+	t.numValidators++
+	t.add(&t.validatorWeight, staker.Weight)
+	t.add(&t.potentialReward, staker.PotentialReward)
+}
+
+func (t *stakerTotals) removeValidator(staker *Staker) {
+	t.numValidators--
+	t.sub(&t.validatorWeight, staker.Weight)
+	t.sub(&t.potentialReward, staker.PotentialReward)
+}
+
+func (t *stakerTotals) addDelegator(staker *Staker) {
+	t.numDelegators++
+	t.add(&t.delegatorWeight, staker.Weight)
+	t.add(&t.potentialReward, staker.PotentialReward)
+}
+
+func (t *stakerTotals) removeDelegator(staker *Staker) {
+	t.numDelegators--
+	t.sub(&t.delegatorWeight, staker.Weight)
+	t.sub(&t.potentialReward, staker.PotentialReward)
+}
+
+func (t *stakerTotals) add(sum *uint64, amount uint64) {
+	newSum, err := safemath.Add(*sum, amount)
+	if err != nil {
+		t.overflowed = true
+		return
+	}
+	*sum = newSum
+}
+
+func (t *stakerTotals) sub(sum *uint64, amount uint64) {
+	newSum, err := safemath.Sub(*sum, amount)
+	if err != nil {
+		t.overflowed = true
+		return
+	}
+	*sum = newSum
+}
+
+func newBaseStakers() *baseStakers {
+	return &baseStakers{
+		validators:     make(map[ids.ID]map[ids.NodeID]*baseStaker),
+		stakers:        btree.NewG(defaultTreeDegree, (*Staker).Less),
+		validatorDiffs: make(map[ids.ID]map[ids.NodeID]*diffValidator),
+
+		validatorsByWeight: make(map[ids.ID]*btree.BTreeG[*Staker]),
+		subnetCounts:       make(map[ids.ID]*subnetStakerCounts),
+		lastActivity:       make(map[ids.ID]time.Time),
+	}
+}
+
+// Clone returns a copy of [v] that can be modified without modifying [v].
+// Stakers are shared between the copies, as they are never modified in place.
+//
+// Copying the maps takes time linear in the number of validators. The staker
+// trees are copied lazily, so each tree is only fully copied once either copy
+// modifies it.
+func (v *baseStakers) Clone() *baseStakers {
+	clone := &baseStakers{
+		validators:     make(map[ids.ID]map[ids.NodeID]*baseStaker, len(v.validators)),
+		stakers:        v.stakers.Clone(),
+		validatorDiffs: make(map[ids.ID]map[ids.NodeID]*diffValidator, len(v.validatorDiffs)),
+		totals:         v.totals,
+
+		validatorsByWeight: make(map[ids.ID]*btree.BTreeG[*Staker], len(v.validatorsByWeight)),
+		subnetCounts:       make(map[ids.ID]*subnetStakerCounts, len(v.subnetCounts)),
+		lastActivity:       maps.Clone(v.lastActivity),
+		timestamp:          v.timestamp,
+	}
+	for subnetID, subnetValidators := range v.validators {
+		clonedValidators := make(map[ids.NodeID]*baseStaker, len(subnetValidators))
+		for nodeID, validator := range subnetValidators {
+			clonedValidators[nodeID] = &baseStaker{
+				validator:  validator.validator,
+				delegators: cloneTree(validator.delegators),
+			}
+		}
+		clone.validators[subnetID] = clonedValidators
+	}
+	for subnetID, subnetValidatorDiffs := range v.validatorDiffs {
+		clonedDiffs := make(map[ids.NodeID]*diffValidator, len(subnetValidatorDiffs))
+		for nodeID, validatorDiff := range subnetValidatorDiffs {
+			clonedDiff := *validatorDiff
+			clonedDiff.addedDelegators = cloneTree(validatorDiff.addedDelegators)
+			clonedDiff.deletedDelegators = maps.Clone(validatorDiff.deletedDelegators)
+			clonedDiffs[nodeID] = &clonedDiff
+		}
+		clone.validatorDiffs[subnetID] = clonedDiffs
+	}
+	for subnetID, validators := range v.validatorsByWeight {
+		clone.validatorsByWeight[subnetID] = validators.Clone()
+	}
+	for subnetID, counts := range v.subnetCounts {
+		clonedCounts := *counts
+		clone.subnetCounts[subnetID] = &clonedCounts
+	}
+	return clone
+}
+
+// newMeteredBaseStakers returns an empty baseStakers that reports its stakers
+// to [metrics]. Any stakers previously reported to [metrics] are removed.
+func newMeteredBaseStakers(metrics *stakerMetrics) *baseStakers {
+	metrics.reset()
+	v := newBaseStakers()
+	v.metrics = metrics
+	return v
+}
+
+func (v *baseStakers) GetValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error) {
+	subnetValidators, ok := v.validators[subnetID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	validator, ok := subnetValidators[nodeID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	if validator.validator == nil {
+		return nil, database.ErrNotFound
+	}
+	return validator.validator, nil
+}
+
+// SetTimestamp sets the chain time at which subsequent additions and removals
+// of stakers occur.
+func (v *baseStakers) SetTimestamp(tm time.Time) {
+	v.timestamp = tm
+}
+
+func (v *baseStakers) PutValidator(staker *Staker) {
+	v.loadValidator(staker)
+	v.recordActivity(staker.SubnetID)
+
+	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
+	validatorDiff.validatorStatus = added
+	validatorDiff.validator = staker
+}
+
+func (v *baseStakers) DeleteValidator(staker *Staker) {
+	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
+	if validator.validator != nil {
+		v.totals.removeValidator(validator.validator)
+		v.unindexValidator(validator.validator)
+		v.subPotentialReward(validator.validator)
+		v.updateCounts(staker.SubnetID, -1, 0)
+		v.recordActivity(staker.SubnetID)
+	}
+	validator.validator = nil
+	v.pruneValidator(staker.SubnetID, staker.NodeID)
+
+	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
+	validatorDiff.validatorStatus = deleted
+	validatorDiff.validator = staker
+
+	v.stakers.Delete(staker)
+}
+
+func (v *baseStakers) GetDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID) iterator.Iterator[*Staker] {
+	subnetValidators, ok := v.validators[subnetID]
+	if !ok {
+		return iterator.Empty[*Staker]{}
+	}
+	validator, ok := subnetValidators[nodeID]
+	if !ok {
+		return iterator.Empty[*Staker]{}
+	}
+	return iterator.FromTree(validator.delegators)
+}
+
+// NumDelegators returns the number of delegators of the validator on
+// [subnetID] with [nodeID]. If the validator is unknown, 0 is returned.
+func (v *baseStakers) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	subnetValidators, ok := v.validators[subnetID]
+	if !ok {
+		return 0
+	}
+	validator, ok := subnetValidators[nodeID]
+	if !ok {
+		return 0
+	}
+	return validator.numDelegators()
+}
+
+func (v *baseStakers) PutDelegator(staker *Staker) {
+	v.loadDelegator(staker)
+	v.recordActivity(staker.SubnetID)
+
+	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
+	if validatorDiff.addedDelegators == nil {
+		validatorDiff.addedDelegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
+	}
+	validatorDiff.addedDelegators.ReplaceOrInsert(staker)
 }
 
-type baseStaker struct {
-	validator  *Staker
-	delegators *btree.BTreeG[*Staker]
+func (v *baseStakers) DeleteDelegator(staker *Staker) {
+	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
+	if validator.delegators != nil {
+		if deleted, ok := validator.delegators.Delete(staker); ok {
+			v.totals.removeDelegator(deleted)
+			v.subPotentialReward(deleted)
+			v.updateCounts(staker.SubnetID, 0, -1)
+			v.recordActivity(staker.SubnetID)
+		}
+	}
+	v.pruneValidator(staker.SubnetID, staker.NodeID)
+
+	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
+	if validatorDiff.deletedDelegators == nil {
+		validatorDiff.deletedDelegators = make(map[ids.ID]*Staker)
+	}
+	validatorDiff.deletedDelegators[staker.TxID] = staker
+
+	v.stakers.Delete(staker)
+}
+
+// DeleteValidatorCascade removes the validator on [subnetID] with [nodeID]
+// along with all of its delegators. The number of removed delegators is
+// returned. If the validator does not exist, [database.ErrNotFound] is
+// returned.
+func (v *baseStakers) DeleteValidatorCascade(subnetID ids.ID, nodeID ids.NodeID) (int, error) {
+	validator, ok := v.validators[subnetID][nodeID]
+	if !ok || validator.validator == nil {
+		return 0, database.ErrNotFound
+	}
+
+	var delegators []*Staker
+	if validator.delegators != nil {
+		delegators = make([]*Staker, 0, validator.delegators.Len())
+		validator.delegators.Ascend(func(delegator *Staker) bool {
+			delegators = append(delegators, delegator)
+			return true
+		})
+	}
+
+	for _, delegator := range delegators {
+		v.DeleteDelegator(delegator)
+	}
+	v.DeleteValidator(validator.validator)
+	return len(delegators), nil
+}
+
+// PutValidators adds each of [stakers] as a validator. The resulting state is
+// identical to calling PutValidator with each staker in order.
+func (v *baseStakers) PutValidators(stakers []*Staker) {
+	v.reserve(stakers)
+	for _, staker := range stakers {
+		v.PutValidator(staker)
+	}
+}
+
+// PutDelegators adds each of [stakers] as a delegator. The resulting state is
+// identical to calling PutDelegator with each staker in order.
+func (v *baseStakers) PutDelegators(stakers []*Staker) {
+	v.reserve(stakers)
+
+	// Group the delegators by validator so that each validator only needs to be
+	// looked up once.
+	type validatorKey struct {
+		subnetID ids.ID
+		nodeID   ids.NodeID
+	}
+	var (
+		keys   []validatorKey
+		groups = make(map[validatorKey][]*Staker)
+	)
+	for _, staker := range stakers {
+		key := validatorKey{
+			subnetID: staker.SubnetID,
+			nodeID:   staker.NodeID,
+		}
+		group, ok := groups[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(group, staker)
+	}
+
+	for _, key := range keys {
+		var (
+			validator     = v.getOrCreateValidator(key.subnetID, key.nodeID)
+			validatorDiff = v.getOrCreateValidatorDiff(key.subnetID, key.nodeID)
+		)
+		if validatorDiff.addedDelegators == nil {
+			validatorDiff.addedDelegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
+		}
+		for _, staker := range groups[key] {
+			v.insertDelegator(validator, staker)
+			validatorDiff.addedDelegators.ReplaceOrInsert(staker)
+		}
+		v.recordActivity(key.subnetID)
+	}
+
+	for _, staker := range stakers {
+		v.stakers.ReplaceOrInsert(staker)
+	}
+}
+
+// DeduplicateDelegators removes any delegators of the validator on
+// [subnetID] with [nodeID] that share a TxID with an earlier delegator. The
+// number of removed delegators is returned.
+//
+// Delegators are persisted by TxID, so a duplicate is never written as its own
+// entry. Removing a duplicate therefore only cancels its pending addition,
+// leaving the entry of the delegator that is kept untouched.
+func (v *baseStakers) DeduplicateDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	subnetValidators, ok := v.validators[subnetID]
+	if !ok {
+		return 0
+	}
+	validator, ok := subnetValidators[nodeID]
+	if !ok || validator.delegators == nil {
+		return 0
+	}
+
+	var (
+		seen       = set.NewSet[ids.ID](validator.delegators.Len())
+		duplicates []*Staker
+	)
+	validator.delegators.Ascend(func(delegator *Staker) bool {
+		if seen.Contains(delegator.TxID) {
+			duplicates = append(duplicates, delegator)
+		} else {
+			seen.Add(delegator.TxID)
+		}
+		return true
+	})
+
+	for _, delegator := range duplicates {
+		v.DeleteDelegator(delegator)
+
+		validatorDiff := v.getOrCreateValidatorDiff(subnetID, nodeID)
+		delete(validatorDiff.deletedDelegators, delegator.TxID)
+		if validatorDiff.addedDelegators != nil {
+			validatorDiff.addedDelegators.Delete(delegator)
+		}
+	}
+	return len(duplicates)
+}
+
+// FindCrossSubnetDuplicateTxIDs returns the stakers, grouped by TxID, of any
+// TxID that is used by stakers on more than one subnet. The stakers of each
+// TxID are ordered by SubnetID and then by NodeID.
+func (v *baseStakers) FindCrossSubnetDuplicateTxIDs() map[ids.ID][]*Staker {
+	stakersByTxID := make(map[ids.ID][]*Staker)
+	for _, subnetValidators := range v.validators {
+		for _, validator := range subnetValidators {
+			if validator.validator != nil {
+				txID := validator.validator.TxID
+				stakersByTxID[txID] = append(stakersByTxID[txID], validator.validator)
+			}
+			if validator.delegators != nil {
+				validator.delegators.Ascend(func(delegator *Staker) bool {
+					stakersByTxID[delegator.TxID] = append(stakersByTxID[delegator.TxID], delegator)
+					return true
+				})
+			}
+		}
+	}
+
+	duplicates := make(map[ids.ID][]*Staker)
+	for txID, stakers := range stakersByTxID {
+		subnetID := stakers[0].SubnetID
+		isCrossSubnet := slices.ContainsFunc(stakers[1:], func(staker *Staker) bool {
+			return staker.SubnetID != subnetID
+		})
+		if !isCrossSubnet {
+			continue
+		}
+
+		slices.SortFunc(stakers, func(a, b *Staker) int {
+			if c := a.SubnetID.Compare(b.SubnetID); c != 0 {
+				return c
+			}
+			return a.NodeID.Compare(b.NodeID)
+		})
+		duplicates[txID] = stakers
+	}
+	return duplicates
+}
+
+func (v *baseStakers) GetStakerIterator() iterator.Iterator[*Staker] {
+	return iterator.FromTree(v.stakers)
+}
+
+// GetValidatorsByWeight returns the validators on [subnetID] whose weight is in
+// [minWeight, maxWeight], ordered by weight and then by NodeID.
+func (v *baseStakers) GetValidatorsByWeight(subnetID ids.ID, minWeight, maxWeight uint64) iterator.Iterator[*Staker] {
+	subnetValidators, ok := v.validatorsByWeight[subnetID]
+	if !ok || minWeight > maxWeight {
+		return iterator.Empty[*Staker]{}
+	}
+
+	var validators []*Staker
+	subnetValidators.AscendGreaterOrEqual(
+		&Staker{Weight: minWeight},
+		func(validator *Staker) bool {
+			if validator.Weight > maxWeight {
+				return false
+			}
+			validators = append(validators, validator)
+			return true
+		},
+	)
+	if len(validators) == 0 {
+		return iterator.Empty[*Staker]{}
+	}
+	return iterator.FromSlice(validators...)
+}
+
+// GetStakerReverseIterator returns the stakers in the reverse order of
+// [GetStakerIterator].
+func (v *baseStakers) GetStakerReverseIterator() iterator.Iterator[*Staker] {
+	return iterator.FromTreeReverse(v.stakers)
+}
+
+// GetAllStakersIterator returns the validators and delegators on every subnet
+// ordered by SubnetID, then NextTime, then TxID.
+func (v *baseStakers) GetAllStakersIterator() iterator.Iterator[*Staker] {
+	stakers := make([]*Staker, 0, v.stakers.Len())
+	v.stakers.Ascend(func(staker *Staker) bool {
+		stakers = append(stakers, staker)
+		return true
+	})
+	slices.SortFunc(stakers, func(a, b *Staker) int {
+		if c := a.SubnetID.Compare(b.SubnetID); c != 0 {
+			return c
+		}
+		if c := a.NextTime.Compare(b.NextTime); c != 0 {
+			return c
+		}
+		return a.TxID.Compare(b.TxID)
+	})
+	return iterator.FromSlice(stakers...)
+}
+
+// GetValidatorIteratorByTxID returns the validators on [subnetID] in order of
+// increasing TxID.
+func (v *baseStakers) GetValidatorIteratorByTxID(subnetID ids.ID) iterator.Iterator[*Staker] {
+	validators := v.subnetValidators(subnetID)
+	slices.SortFunc(validators, func(a, b *Staker) int {
+		return a.TxID.Compare(b.TxID)
+	})
+	return iterator.FromSlice(validators...)
+}
+
+// AverageValidatorWeight returns the mean weight of the validators on
+// [subnetID].
+func (v *baseStakers) AverageValidatorWeight(subnetID ids.ID) (float64, error) {
+	validators := v.subnetValidators(subnetID)
+	if len(validators) == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	totalWeight, err := sumWeights(stakerWeights(validators))
+	if err != nil {
+		return 0, err
+	}
+	return float64(totalWeight) / float64(len(validators)), nil
+}
+
+// DelegatorValidatorRatio returns the number of delegators on [subnetID]
+// divided by the number of validators on [subnetID].
+func (v *baseStakers) DelegatorValidatorRatio(subnetID ids.ID) (float64, error) {
+	counts, ok := v.subnetCounts[subnetID]
+	if !ok || counts.validators == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	return float64(counts.delegators) / float64(counts.validators), nil
+}
+
+// ValidatorWeightVariance returns the population variance of the weights of
+// the validators on [subnetID].
+func (v *baseStakers) ValidatorWeightVariance(subnetID ids.ID) (float64, error) {
+	mean, err := v.AverageValidatorWeight(subnetID)
+	if err != nil {
+		return 0, err
+	}
+
+	validators := v.subnetValidators(subnetID)
+	var sumSquaredDeviations float64
+	for _, validator := range validators {
+		deviation := float64(validator.Weight) - mean
+		sumSquaredDeviations += deviation * deviation
+	}
+	return sumSquaredDeviations / float64(len(validators)), nil
+}
+
+// StakeCoefficientOfVariation returns the population standard deviation of
+// the weights of the validators on [subnetID] divided by their mean.
+func (v *baseStakers) StakeCoefficientOfVariation(subnetID ids.ID) (float64, error) {
+	mean, err := v.AverageValidatorWeight(subnetID)
+	if err != nil {
+		return 0, err
+	}
+	if mean == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+
+	variance, err := v.ValidatorWeightVariance(subnetID)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(variance) / mean, nil
+}
+
+// RewardShares returns the fraction of the total potential reward of the
+// validators on [subnetID] that each validator would receive. If the total
+// potential reward is zero, an empty map is returned.
+func (v *baseStakers) RewardShares(subnetID ids.ID) map[ids.NodeID]float64 {
+	var (
+		validators  = v.subnetValidators(subnetID)
+		totalReward float64
+	)
+	for _, validator := range validators {
+		// The total reward is summed as a float to avoid overflows.
+		totalReward += float64(validator.PotentialReward)
+	}
+	if totalReward == 0 {
+		return map[ids.NodeID]float64{}
+	}
+
+	shares := make(map[ids.NodeID]float64, len(validators))
+	for _, validator := range validators {
+		shares[validator.NodeID] = float64(validator.PotentialReward) / totalReward
+	}
+	return shares
+}
+
+// WeightChangesSince returns the change in weight of each validator on
+// [subnetID] relative to [snapshot]. Validators missing from [snapshot] are
+// treated as having had no weight, and validators in [snapshot] that no longer
+// exist are treated as having no weight. Unchanged validators are omitted.
+func (v *baseStakers) WeightChangesSince(subnetID ids.ID, snapshot map[ids.NodeID]uint64) map[ids.NodeID]int64 {
+	changes := make(map[ids.NodeID]int64)
+	for _, validator := range v.subnetValidators(subnetID) {
+		if delta := int64(validator.Weight) - int64(snapshot[validator.NodeID]); delta != 0 {
+			changes[validator.NodeID] = delta
+		}
+	}
+	for nodeID, weight := range snapshot {
+		if _, err := v.GetValidator(subnetID, nodeID); err == nil || weight == 0 {
+			continue
+		}
+		changes[nodeID] = -int64(weight)
+	}
+	return changes
+}
+
+// TotalWeightAcrossSubnets returns the combined weight of the validators on
+// each of [subnetIDs]. Duplicate subnetIDs are only counted once.
+func (v *baseStakers) TotalWeightAcrossSubnets(subnetIDs []ids.ID) (uint64, error) {
+	var total uint64
+	for subnetID := range set.Of(subnetIDs...) {
+		weight, err := sumWeights(stakerWeights(v.subnetValidators(subnetID)))
+		if err != nil {
+			return 0, err
+		}
+		total, err = safemath.Add(total, weight)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// SubnetsByTotalStake returns each subnet with at least one validator along
+// with the combined weight of its validators. Subnets are sorted from the
+// largest to the smallest weight, with ties broken by SubnetID.
+func (v *baseStakers) SubnetsByTotalStake() []SubnetStake {
+	subnets := make([]SubnetStake, 0, len(v.validators))
+	for subnetID := range v.validators {
+		validators := v.subnetValidators(subnetID)
+		if len(validators) == 0 {
+			continue
+		}
+
+		weight, err := sumWeights(stakerWeights(validators))
+		if err != nil {
+			weight = safemath.MaxUint[uint64]()
+		}
+		subnets = append(subnets, SubnetStake{
+			SubnetID: subnetID,
+			Weight:   weight,
+		})
+	}
+	slices.SortFunc(subnets, func(a, b SubnetStake) int {
+		if c := cmp.Compare(b.Weight, a.Weight); c != 0 {
+			return c
+		}
+		return a.SubnetID.Compare(b.SubnetID)
+	})
+	return subnets
+}
+
+// TotalPendingReward returns the sum of the potential rewards of the current
+// validators and delegators on [subnetID]. If the sum exceeds a uint64,
+// [safemath.ErrOverflow] is returned.
+func (v *baseStakers) TotalPendingReward(subnetID ids.ID) (uint64, error) {
+	counts, ok := v.subnetCounts[subnetID]
+	if !ok {
+		return 0, nil
+	}
+	return counts.potentialReward.value()
+}
+
+// IdleSubnets returns the subnets with stakers that have not had a staker added
+// or removed at or after [since], sorted by SubnetID. Additions and removals
+// are recorded at the timestamp set when they occurred. Stakers loaded from
+// disk are not considered activity.
+func (v *baseStakers) IdleSubnets(since time.Time) []ids.ID {
+	var idle []ids.ID
+	for subnetID := range v.subnetCounts {
+		if v.lastActivity[subnetID].Before(since) {
+			idle = append(idle, subnetID)
+		}
+	}
+	utils.Sort(idle)
+	return idle
+}
+
+// MostDelegatedValidator returns the validator on [subnetID] with the largest
+// total delegator weight along with that weight. Ties are broken by the lesser
+// NodeID.
+func (v *baseStakers) MostDelegatedValidator(subnetID ids.ID) (*Staker, uint64, error) {
+	var (
+		mostDelegated *Staker
+		mostWeight    uint64
+	)
+	for _, validator := range v.validators[subnetID] {
+		if validator.validator == nil {
+			continue
+		}
+		weight, err := validator.delegatorWeight()
+		if err != nil {
+			return nil, 0, err
+		}
+		if mostDelegated == nil ||
+			weight > mostWeight ||
+			(weight == mostWeight && validator.validator.NodeID.Compare(mostDelegated.NodeID) < 0) {
+			mostDelegated = validator.validator
+			mostWeight = weight
+		}
+	}
+	if mostDelegated == nil {
+		return nil, 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	return mostDelegated, mostWeight, nil
+}
+
+// PartitionDelegators splits the delegators of the validator on [subnetID]
+// with [nodeID] into [groups] slices whose lengths differ by at most one.
+// Delegators are assigned to the groups in order of their removal from the
+// staker set. If [groups] isn't positive, nil is returned.
+func (v *baseStakers) PartitionDelegators(subnetID ids.ID, nodeID ids.NodeID, groups int) [][]*Staker {
+	if groups <= 0 {
+		return nil
+	}
+
+	var delegators []*Staker
+	if validator, ok := v.validators[subnetID][nodeID]; ok && validator.delegators != nil {
+		delegators = make([]*Staker, 0, validator.delegators.Len())
+		validator.delegators.Ascend(func(delegator *Staker) bool {
+			delegators = append(delegators, delegator)
+			return true
+		})
+	}
+
+	var (
+		partitions = make([][]*Staker, groups)
+		groupSize  = len(delegators) / groups
+		remainder  = len(delegators) % groups
+	)
+	for i := range partitions {
+		size := groupSize
+		if i < remainder {
+			size++
+		}
+		partitions[i] = delegators[:size:size]
+		delegators = delegators[size:]
+	}
+	return partitions
+}
+
+// TimeWeightedAverageStake returns the average amount of stake, including
+// delegations, that is active on [subnetID] over [from, to]. Stakers are
+// considered active over [StartTime, EndTime).
+func (v *baseStakers) TimeWeightedAverageStake(subnetID ids.ID, from, to time.Time) (float64, error) {
+	if !from.Before(to) {
+		return 0, fmt.Errorf("%w: [%s, %s]", errInvalidTimeWindow, from, to)
+	}
+
+	var weightedStake float64
+	for _, staker := range v.subnetStakers(subnetID) {
+		start := staker.StartTime
+		if start.Before(from) {
+			start = from
+		}
+		end := staker.EndTime
+		if end.After(to) {
+			end = to
+		}
+		if !start.Before(end) {
+			continue
+		}
+		weightedStake += float64(staker.Weight) * end.Sub(start).Seconds()
+	}
+	return weightedStake / to.Sub(from).Seconds(), nil
+}
+
+// ExportStakers returns the stakers on [subnetID] with one of [priorities]
+// that are active at some point in [from, to), ordered by their removal from
+// the staker set. Stakers are considered active over [StartTime, EndTime).
+func (v *baseStakers) ExportStakers(
+	subnetID ids.ID,
+	from time.Time,
+	to time.Time,
+	priorities set.Set[txs.Priority],
+) []*Staker {
+	var stakers []*Staker
+	v.stakers.Ascend(func(staker *Staker) bool {
+		if staker.SubnetID == subnetID &&
+			priorities.Contains(staker.Priority) &&
+			staker.StartTime.Before(to) &&
+			from.Before(staker.EndTime) {
+			stakers = append(stakers, staker)
+		}
+		return true
+	})
+	return stakers
+}
+
+// ExpirationBuckets returns the number of stakers on [subnetID] whose EndTime
+// falls into each [bucket] sized window. Windows are keyed by their start time,
+// as returned by [time.Time.Truncate].
+func (v *baseStakers) ExpirationBuckets(subnetID ids.ID, bucket time.Duration) map[time.Time]int {
+	buckets := make(map[time.Time]int)
+	for _, staker := range v.subnetStakers(subnetID) {
+		buckets[staker.EndTime.Truncate(bucket)]++
+	}
+	return buckets
+}
+
+// ExpiriesByDay returns the number of stakers on [subnetID] whose EndTime
+// falls on each calendar day in [loc]. Days are keyed by their
+// [time.DateOnly] representation.
+func (v *baseStakers) ExpiriesByDay(subnetID ids.ID, loc *time.Location) map[string]int {
+	days := make(map[string]int)
+	for _, staker := range v.subnetStakers(subnetID) {
+		days[staker.EndTime.In(loc).Format(time.DateOnly)]++
+	}
+	return days
+}
+
+// ValidatorRotationRate returns the average number of validators on
+// [subnetID] that are expected to expire per day over (now, now + window],
+// based on the EndTimes of the current validators.
+func (v *baseStakers) ValidatorRotationRate(subnetID ids.ID, window time.Duration, now time.Time) (float64, error) {
+	if window <= 0 {
+		return 0, fmt.Errorf("%w: %s", errInvalidTimeWindow, window)
+	}
+
+	end := now.Add(window)
+	var numExpiring int
+	for _, validator := range v.subnetValidators(subnetID) {
+		if validator.EndTime.After(now) && !validator.EndTime.After(end) {
+			numExpiring++
+		}
+	}
+	days := float64(window) / float64(24*time.Hour)
+	return float64(numExpiring) / days, nil
+}
+
+// UpcomingStakers returns the stakers on [subnetID] whose NextTime is in
+// (now, now+lookahead], ordered by their removal from the staker set.
+func (v *baseStakers) UpcomingStakers(subnetID ids.ID, now time.Time, lookahead time.Duration) []*Staker {
+	var (
+		end      = now.Add(lookahead)
+		upcoming []*Staker
+	)
+	v.stakers.Ascend(func(staker *Staker) bool {
+		if staker.NextTime.After(end) {
+			return false
+		}
+		if staker.SubnetID == subnetID && staker.NextTime.After(now) {
+			upcoming = append(upcoming, staker)
+		}
+		return true
+	})
+	return upcoming
+}
+
+// ExpiryQueuePosition returns the zero-based index of the staker with [txID]
+// among the stakers on [subnetID], ordered by their removal from the staker
+// set. If the staker does not exist, [database.ErrNotFound] is returned.
+func (v *baseStakers) ExpiryQueuePosition(subnetID ids.ID, txID ids.ID) (int, error) {
+	var (
+		position int
+		found    bool
+	)
+	v.stakers.Ascend(func(staker *Staker) bool {
+		if staker.SubnetID != subnetID {
+			return true
+		}
+		if staker.TxID == txID {
+			found = true
+			return false
+		}
+		position++
+		return true
+	})
+	if !found {
+		return 0, database.ErrNotFound
+	}
+	return position, nil
+}
+
+// StakeConcentration returns the fraction of the total validator weight on
+// [subnetID] that is held by the [k] heaviest validators. If [k] exceeds the
+// number of validators, all validators are considered.
+func (v *baseStakers) StakeConcentration(subnetID ids.ID, k int) (float64, error) {
+	weights := v.validatorWeightsDescending(subnetID)
+	if len(weights) == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	totalWeight, err := sumWeights(weights)
+	if err != nil {
+		return 0, err
+	}
+	if totalWeight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+
+	k = max(k, 0)
+	k = min(k, len(weights))
+	topWeight, err := sumWeights(weights[:k])
+	if err != nil {
+		return 0, err
+	}
+	return float64(topWeight) / float64(totalWeight), nil
+}
+
+// StakeHerfindahlIndex returns the sum of the squared shares of the total
+// validator weight on [subnetID] held by each validator.
+func (v *baseStakers) StakeHerfindahlIndex(subnetID ids.ID) (float64, error) {
+	weights := v.validatorWeightsDescending(subnetID)
+	if len(weights) == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	totalWeight, err := sumWeights(weights)
+	if err != nil {
+		return 0, err
+	}
+	if totalWeight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+	return herfindahlIndex(weights, totalWeight), nil
+}
+
+// NakamotoCoefficient returns the minimum number of validators on [subnetID]
+// whose combined weight exceeds one third of the total validator weight. This
+// is the smallest set of validators able to block consensus.
+func (v *baseStakers) NakamotoCoefficient(subnetID ids.ID) (int, error) {
+	weights := v.validatorWeightsDescending(subnetID)
+	if len(weights) == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	totalWeight, err := sumWeights(weights)
+	if err != nil {
+		return 0, err
+	}
+	if totalWeight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+
+	// For integers, 3*weight > totalWeight iff weight > totalWeight/3.
+	threshold := totalWeight / 3
+	var accumulatedWeight uint64
+	for i, weight := range weights {
+		accumulatedWeight += weight
+		if accumulatedWeight > threshold {
+			return i + 1, nil
+		}
+	}
+	return len(weights), nil
+}
+
+// MinValidatorsForThreshold returns the minimum number of validators on
+// [subnetID] whose combined weight is at least [threshold].
+func (v *baseStakers) MinValidatorsForThreshold(subnetID ids.ID, threshold uint64) (int, error) {
+	var (
+		weights           = v.validatorWeightsDescending(subnetID)
+		accumulatedWeight uint64
+		err               error
+	)
+	for i, weight := range weights {
+		if accumulatedWeight >= threshold {
+			return i, nil
+		}
+		accumulatedWeight, err = safemath.Add(accumulatedWeight, weight)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if accumulatedWeight >= threshold {
+		return len(weights), nil
+	}
+	return 0, fmt.Errorf("%w: %d > %d on %s", errUnreachableStake, threshold, accumulatedWeight, subnetID)
+}
+
+// SimulateAddValidator returns the total validator weight and the
+// [StakeHerfindahlIndex] of [subnetID] that would result from adding a
+// validator with [weight]. The staker set is not modified.
+func (v *baseStakers) SimulateAddValidator(subnetID ids.ID, weight uint64) (uint64, float64, error) {
+	weights := append(v.validatorWeightsDescending(subnetID), weight)
+	totalWeight, err := sumWeights(weights)
+	if err != nil {
+		return 0, 0, err
+	}
+	if totalWeight == 0 {
+		return 0, 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+	return totalWeight, herfindahlIndex(weights, totalWeight), nil
+}
+
+// StakeQuantiles returns the validator weights on [subnetID] at each of the
+// quantiles in [qs]. Quantiles must be in [0, 1] and are linearly interpolated
+// between the closest ranks, rounding to the nearest weight.
+func (v *baseStakers) StakeQuantiles(subnetID ids.ID, qs []float64) ([]uint64, error) {
+	weights := v.validatorWeightsDescending(subnetID)
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	slices.Reverse(weights)
+
+	quantiles := make([]uint64, len(qs))
+	for i, q := range qs {
+		if q < 0 || q > 1 {
+			return nil, fmt.Errorf("%w: %f", errInvalidQuantile, q)
+		}
+
+		var (
+			rank     = q * float64(len(weights)-1)
+			lower    = int(rank)
+			fraction = rank - float64(lower)
+			weight   = float64(weights[lower])
+		)
+		if fraction > 0 {
+			weight += fraction * (float64(weights[lower+1]) - weight)
+		}
+		quantiles[i] = uint64(math.Round(weight))
+	}
+	return quantiles, nil
+}
+
+// WeightedMedianReward returns the PotentialReward of the validator at the
+// stake-weighted median of the validators on [subnetID], when ordered by
+// PotentialReward. If the median falls exactly between two validators, the
+// lower reward is returned.
+func (v *baseStakers) WeightedMedianReward(subnetID ids.ID) (uint64, error) {
+	validators := v.subnetValidators(subnetID)
+	if len(validators) == 0 {
+		return 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+
+	totalWeight, err := sumWeights(stakerWeights(validators))
+	if err != nil {
+		return 0, err
+	}
+	if totalWeight == 0 {
+		return 0, fmt.Errorf("%w: %s", errZeroWeight, subnetID)
+	}
+
+	slices.SortFunc(validators, func(a, b *Staker) int {
+		return cmp.Compare(a.PotentialReward, b.PotentialReward)
+	})
+
+	var accumulatedWeight uint64
+	for _, validator := range validators {
+		accumulatedWeight += validator.Weight
+		if accumulatedWeight >= totalWeight-accumulatedWeight {
+			return validator.PotentialReward, nil
+		}
+	}
+	return validators[len(validators)-1].PotentialReward, nil
+}
+
+// ParticipationRate returns the fraction of [totalSupply] that is staked on
+// [subnetID] by validators and delegators.
+func (v *baseStakers) ParticipationRate(subnetID ids.ID, totalSupply uint64) (float64, error) {
+	if totalSupply == 0 {
+		return 0, errZeroSupply
+	}
+	stakedWeight, err := sumWeights(stakerWeights(v.subnetStakers(subnetID)))
+	if err != nil {
+		return 0, err
+	}
+	return float64(stakedWeight) / float64(totalSupply), nil
+}
+
+// ProjectedStakeAfter returns the combined weight of the current validators and
+// delegators on [subnetID] that will not have expired [duration] after [now].
+func (v *baseStakers) ProjectedStakeAfter(subnetID ids.ID, now time.Time, duration time.Duration) (uint64, error) {
+	var (
+		at        = now.Add(duration)
+		stakers   = v.subnetStakers(subnetID)
+		unexpired = stakers[:0]
+	)
+	for _, staker := range stakers {
+		if staker.Priority.IsCurrent() && staker.EndTime.After(at) {
+			unexpired = append(unexpired, staker)
+		}
+	}
+	return sumWeights(stakerWeights(unexpired))
+}
+
+// SlashValidator marks the validator on [subnetID] with [nodeID] as slashed.
+// If the validator does not exist, [database.ErrNotFound] is returned.
+//
+// Slashing only modifies the in-memory staker set and is not persisted, so it
+// must not influence any state that is agreed upon by consensus. In particular,
+// the validator's PotentialReward is left unmodified and is still paid out by
+// the RewardValidatorTx.
+func (v *baseStakers) SlashValidator(subnetID ids.ID, nodeID ids.NodeID) error {
+	validator, ok := v.validators[subnetID][nodeID]
+	if !ok || validator.validator == nil {
+		return database.ErrNotFound
+	}
+
+	// Slashing doesn't modify the ordering of the staker, so it can be
+	// replaced in place.
+	slashed := *validator.validator
+	slashed.Slashed = true
+	validator.validator = &slashed
+	v.stakers.ReplaceOrInsert(&slashed)
+	v.indexValidator(&slashed)
+	return nil
+}
+
+// GetSlashedValidators returns the slashed validators on [subnetID] in order of
+// their removal from the staker set.
+func (v *baseStakers) GetSlashedValidators(subnetID ids.ID) iterator.Iterator[*Staker] {
+	return iterator.Filter(
+		iterator.FromTree(v.stakers),
+		func(staker *Staker) bool {
+			return staker.SubnetID != subnetID ||
+				!staker.Slashed ||
+				!staker.Priority.IsValidator()
+		},
+	)
+}
+
+// FindPendingNextTimeMismatches returns the pending stakers on [subnetID] whose
+// NextTime differs from their StartTime in order of their NextTime.
+func (v *baseStakers) FindPendingNextTimeMismatches(subnetID ids.ID) iterator.Iterator[*Staker] {
+	return iterator.Filter(
+		iterator.FromTree(v.stakers),
+		func(staker *Staker) bool {
+			return staker.SubnetID != subnetID ||
+				!staker.Priority.IsPending() ||
+				staker.NextTime.Equal(staker.StartTime)
+		},
+	)
+}
+
+// UnrewardedEndedValidators returns the current validators on [subnetID] whose
+// EndTime is at or before [now] and that are therefore awaiting their removal
+// and reward. Validators are returned in order of their removal from the
+// staker set.
+func (v *baseStakers) UnrewardedEndedValidators(subnetID ids.ID, now time.Time) iterator.Iterator[*Staker] {
+	return iterator.Filter(
+		iterator.FromTree(v.stakers),
+		func(staker *Staker) bool {
+			return staker.SubnetID != subnetID ||
+				!staker.Priority.IsCurrentValidator() ||
+				staker.EndTime.After(now)
+		},
+	)
+}
+
+// FindInvalidTimeRangeStakers returns the stakers on every subnet whose EndTime
+// isn't after their StartTime in order of their removal from the staker set.
+func (v *baseStakers) FindInvalidTimeRangeStakers() iterator.Iterator[*Staker] {
+	return iterator.Filter(
+		iterator.FromTree(v.stakers),
+		func(staker *Staker) bool {
+			return staker.EndTime.After(staker.StartTime)
+		},
+	)
+}
+
+// FindPriorityMisalignedDelegators returns the delegators on [subnetID] whose
+// priority is current while their validator's is pending, or vice versa.
+// Delegators are returned in order of their removal from the staker set.
+func (v *baseStakers) FindPriorityMisalignedDelegators(subnetID ids.ID) iterator.Iterator[*Staker] {
+	var misaligned []*Staker
+	for _, validator := range v.validators[subnetID] {
+		if validator.validator == nil || validator.delegators == nil {
+			continue
+		}
+		isCurrent := validator.validator.Priority.IsCurrent()
+		validator.delegators.Ascend(func(delegator *Staker) bool {
+			if delegator.Priority.IsCurrent() != isCurrent {
+				misaligned = append(misaligned, delegator)
+			}
+			return true
+		})
+	}
+	slices.SortFunc(misaligned, compareStakers)
+	return iterator.FromSlice(misaligned...)
+}
+
+// DelegationHeadroom returns how much additional weight can be delegated to the
+// validator on [subnetID] with [nodeID] before the combined weight of the
+// validator and its delegators exceeds [maxFactor] times the validator's
+// weight. If the validator does not exist, [database.ErrNotFound] is returned.
+func (v *baseStakers) DelegationHeadroom(subnetID ids.ID, nodeID ids.NodeID, maxFactor uint64) (uint64, error) {
+	validator, ok := v.validators[subnetID][nodeID]
+	if !ok || validator.validator == nil {
+		return 0, database.ErrNotFound
+	}
+
+	maxWeight, err := safemath.Mul(validator.validator.Weight, maxFactor)
+	if err != nil {
+		maxWeight = safemath.MaxUint[uint64]()
+	}
+	delegatorWeight, err := validator.delegatorWeight()
+	if err != nil {
+		return 0, err
+	}
+	currentWeight, err := safemath.Add(validator.validator.Weight, delegatorWeight)
+	if err != nil {
+		return 0, err
+	}
+	if currentWeight >= maxWeight {
+		return 0, nil
+	}
+	return maxWeight - currentWeight, nil
+}
+
+// DelegatableValidators returns the current validators on [subnetID] that are
+// active at [now] and have a positive [DelegationHeadroom] under [maxFactor].
+// Validators are returned in order of their removal from the staker set.
+func (v *baseStakers) DelegatableValidators(subnetID ids.ID, now time.Time, maxFactor uint64) iterator.Iterator[*Staker] {
+	var validators []*Staker
+	for nodeID, validator := range v.validators[subnetID] {
+		staker := validator.validator
+		if staker == nil ||
+			!staker.Priority.IsCurrentValidator() ||
+			now.Before(staker.StartTime) ||
+			!now.Before(staker.EndTime) {
+			continue
+		}
+
+		headroom, err := v.DelegationHeadroom(subnetID, nodeID, maxFactor)
+		if err == nil && headroom > 0 {
+			validators = append(validators, staker)
+		}
+	}
+	slices.SortFunc(validators, compareStakers)
+	return iterator.FromSlice(validators...)
+}
+
+// FindRewardAnomalies returns the validators on [subnetID] whose potential
+// reward divided by the combined potential reward of their delegators exceeds
+// [ratio]. Validators without any delegator reward are skipped, as the ratio
+// is undefined. Validators are returned in order of their removal from the
+// staker set.
+func (v *baseStakers) FindRewardAnomalies(subnetID ids.ID, ratio float64) iterator.Iterator[*Staker] {
+	var anomalies []*Staker
+	for _, validator := range v.validators[subnetID] {
+		if validator.validator == nil || validator.delegators == nil {
+			continue
+		}
+
+		var delegatorReward float64
+		validator.delegators.Ascend(func(delegator *Staker) bool {
+			delegatorReward += float64(delegator.PotentialReward)
+			return true
+		})
+		if delegatorReward == 0 {
+			continue
+		}
+		if float64(validator.validator.PotentialReward)/delegatorReward > ratio {
+			anomalies = append(anomalies, validator.validator)
+		}
+	}
+	slices.SortFunc(anomalies, compareStakers)
+	return iterator.FromSlice(anomalies...)
+}
+
+// CommonValidators returns the sorted IDs of the nodes that are validators of
+// both [subnetA] and [subnetB].
+func (v *baseStakers) CommonValidators(subnetA, subnetB ids.ID) []ids.NodeID {
+	var (
+		validatorsA = v.validators[subnetA]
+		validatorsB = v.validators[subnetB]
+		nodeIDs     []ids.NodeID
+	)
+	for nodeID, validatorA := range validatorsA {
+		if validatorA.validator == nil {
+			continue
+		}
+		if validatorB, ok := validatorsB[nodeID]; ok && validatorB.validator != nil {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	utils.Sort(nodeIDs)
+	return nodeIDs
+}
+
+// FindValidatorsWithoutDelegators returns the validators on [subnetID] that
+// have no delegators in order of their removal from the staker set.
+func (v *baseStakers) FindValidatorsWithoutDelegators(subnetID ids.ID) iterator.Iterator[*Staker] {
+	var validators []*Staker
+	for _, validator := range v.validators[subnetID] {
+		if validator.validator != nil && validator.numDelegators() == 0 {
+			validators = append(validators, validator.validator)
+		}
+	}
+	slices.SortFunc(validators, compareStakers)
+	return iterator.FromSlice(validators...)
+}
+
+// RewardPayoutSchedule returns the expected reward payouts of the validators
+// on [subnetID] sorted by their EndTime. Payouts at the same time are sorted
+// by NodeID.
+func (v *baseStakers) RewardPayoutSchedule(subnetID ids.ID) []RewardEvent {
+	validators := v.subnetValidators(subnetID)
+	schedule := make([]RewardEvent, len(validators))
+	for i, validator := range validators {
+		schedule[i] = RewardEvent{
+			EndTime:         validator.EndTime,
+			NodeID:          validator.NodeID,
+			PotentialReward: validator.PotentialReward,
+		}
+	}
+	slices.SortFunc(schedule, func(a, b RewardEvent) int {
+		if c := a.EndTime.Compare(b.EndTime); c != 0 {
+			return c
+		}
+		return a.NodeID.Compare(b.NodeID)
+	})
+	return schedule
+}
+
+// IsPrimaryNetwork returns true if [subnetID] is the primary network.
+func (*baseStakers) IsPrimaryNetwork(subnetID ids.ID) bool {
+	return subnetID == constants.PrimaryNetworkID
+}
+
+// NearestValidatorEndTime returns the validator on [subnetID] whose EndTime is
+// closest to [target]. Ties are broken towards the earlier EndTime and then by
+// the order of removal from the staker set.
+func (v *baseStakers) NearestValidatorEndTime(subnetID ids.ID, target time.Time) (*Staker, error) {
+	validators := v.subnetValidators(subnetID)
+	if len(validators) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	return slices.MinFunc(validators, func(a, b *Staker) int {
+		var (
+			distanceA = a.EndTime.Sub(target).Abs()
+			distanceB = b.EndTime.Sub(target).Abs()
+		)
+		if c := cmp.Compare(distanceA, distanceB); c != 0 {
+			return c
+		}
+		if c := a.EndTime.Compare(b.EndTime); c != 0 {
+			return c
+		}
+		return compareStakers(a, b)
+	}), nil
+}
+
+// LongestTenuredValidator returns the validator on [subnetID] that has been
+// validating for the longest time as of [now], along with that duration. Ties
+// are broken by the order of removal from the staker set.
+func (v *baseStakers) LongestTenuredValidator(subnetID ids.ID, now time.Time) (*Staker, time.Duration, error) {
+	validators := v.subnetValidators(subnetID)
+	if len(validators) == 0 {
+		return nil, 0, fmt.Errorf("%w: %s", errNoValidators, subnetID)
+	}
+	validator := slices.MinFunc(validators, func(a, b *Staker) int {
+		if c := a.StartTime.Compare(b.StartTime); c != 0 {
+			return c
+		}
+		return compareStakers(a, b)
+	})
+	return validator, now.Sub(validator.StartTime), nil
+}
+
+// NodeDelegationAcrossSubnets returns the total weight delegated to [nodeID]
+// on each subnet. Subnets without any delegators of [nodeID] are omitted.
+func (v *baseStakers) NodeDelegationAcrossSubnets(nodeID ids.NodeID) (map[ids.ID]uint64, error) {
+	delegations := make(map[ids.ID]uint64)
+	for subnetID, subnetValidators := range v.validators {
+		validator, ok := subnetValidators[nodeID]
+		if !ok || validator.numDelegators() == 0 {
+			continue
+		}
+		weight, err := validator.delegatorWeight()
+		if err != nil {
+			return nil, err
+		}
+		delegations[subnetID] = weight
+	}
+	return delegations, nil
+}
+
+// CanonicalValidatorSet returns the validators on [subnetID] sorted by NodeID
+// along with their total weight.
+func (v *baseStakers) CanonicalValidatorSet(subnetID ids.ID) ([]CanonicalValidator, uint64, error) {
+	var (
+		subnetValidators = v.subnetValidators(subnetID)
+		validators       = make([]CanonicalValidator, len(subnetValidators))
+		totalWeight      uint64
+	)
+	for i, validator := range subnetValidators {
+		var err error
+		totalWeight, err = safemath.Add(totalWeight, validator.Weight)
+		if err != nil {
+			return nil, 0, err
+		}
+		validators[i] = CanonicalValidator{
+			NodeID:    validator.NodeID,
+			Weight:    validator.Weight,
+			PublicKey: validator.PublicKey,
+		}
+	}
+	slices.SortFunc(validators, func(a, b CanonicalValidator) int {
+		return a.NodeID.Compare(b.NodeID)
+	})
+	return validators, totalWeight, nil
+}
+
+// ValidatorsExpiringWith returns the other validators on [subnetID] whose
+// EndTime equals that of the validator with [txID] in order of their removal
+// from the staker set. If there is no such validator, no validators are
+// returned.
+func (v *baseStakers) ValidatorsExpiringWith(subnetID ids.ID, txID ids.ID) iterator.Iterator[*Staker] {
+	validators := v.subnetValidators(subnetID)
+	index := slices.IndexFunc(validators, func(validator *Staker) bool {
+		return validator.TxID == txID
+	})
+	if index == -1 {
+		return iterator.Empty[*Staker]{}
+	}
+
+	endTime := validators[index].EndTime
+	var expiring []*Staker
+	for _, validator := range validators {
+		if validator.TxID != txID && validator.EndTime.Equal(endTime) {
+			expiring = append(expiring, validator)
+		}
+	}
+	slices.SortFunc(expiring, compareStakers)
+	return iterator.FromSlice(expiring...)
 }
 
-func newBaseStakers() *baseStakers {
-	return &baseStakers{
-		validators:     make(map[ids.ID]map[ids.NodeID]*baseStaker),
-		stakers:        btree.NewG(defaultTreeDegree, (*Staker).Less),
-		validatorDiffs: make(map[ids.ID]map[ids.NodeID]*diffValidator),
+// ContinuousValidationDuration returns how long the validator on [subnetID]
+// with [nodeID] has been validating since its StartTime as of [now]. If the
+// validator does not exist, [database.ErrNotFound] is returned.
+func (v *baseStakers) ContinuousValidationDuration(subnetID ids.ID, nodeID ids.NodeID, now time.Time) (time.Duration, error) {
+	validator, err := v.GetValidator(subnetID, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	if now.Before(validator.StartTime) {
+		return 0, nil
 	}
+	return now.Sub(validator.StartTime), nil
 }
 
-func (v *baseStakers) GetValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error) {
-	subnetValidators, ok := v.validators[subnetID]
-	if !ok {
-		return nil, database.ErrNotFound
+// VerifyTotals recomputes the aggregates of the staker set from scratch and
+// returns an error if they differ from the incrementally maintained totals,
+// subnet counts, and indices.
+func (v *baseStakers) VerifyTotals() error {
+	var (
+		computed       stakerTotals
+		computedCounts = make(map[ids.ID]*subnetStakerCounts)
+	)
+	for subnetID, subnetValidators := range v.validators {
+		for nodeID, validator := range subnetValidators {
+			counts, ok := computedCounts[subnetID]
+			if !ok {
+				counts = &subnetStakerCounts{}
+				computedCounts[subnetID] = counts
+			}
+			if validator.validator != nil {
+				computed.addValidator(validator.validator)
+				counts.validators++
+				counts.potentialReward.add(validator.validator.PotentialReward)
+
+				var indexed *Staker
+				if validators, ok := v.validatorsByWeight[subnetID]; ok {
+					indexed, _ = validators.Get(validator.validator)
+				}
+				if indexed != validator.validator {
+					return fmt.Errorf("%w: validator %s on subnet %s is not indexed by weight",
+						errTotalsMismatch,
+						nodeID,
+						subnetID,
+					)
+				}
+			}
+			if validator.delegators != nil {
+				validator.delegators.Ascend(func(delegator *Staker) bool {
+					computed.addDelegator(delegator)
+					counts.delegators++
+					counts.potentialReward.add(delegator.PotentialReward)
+					return true
+				})
+			}
+			if counts.validators == 0 && counts.delegators == 0 {
+				delete(computedCounts, subnetID)
+			}
+		}
 	}
-	validator, ok := subnetValidators[nodeID]
-	if !ok {
-		return nil, database.ErrNotFound
+	if computed.overflowed {
+		return fmt.Errorf("%w: recomputing totals", safemath.ErrOverflow)
 	}
-	if validator.validator == nil {
-		return nil, database.ErrNotFound
+	if computed != v.totals {
+		return fmt.Errorf("%w: maintained %+v but computed %+v",
+			errTotalsMismatch,
+			v.totals,
+			computed,
+		)
 	}
-	return validator.validator, nil
+	if numStakers := v.stakers.Len(); numStakers != computed.numValidators+computed.numDelegators {
+		return fmt.Errorf("%w: %d stakers are ordered but %d validators and %d delegators exist",
+			errTotalsMismatch,
+			numStakers,
+			computed.numValidators,
+			computed.numDelegators,
+		)
+	}
+
+	if len(computedCounts) != len(v.subnetCounts) {
+		return fmt.Errorf("%w: maintained counts for %d subnets but computed %d",
+			errTotalsMismatch,
+			len(v.subnetCounts),
+			len(computedCounts),
+		)
+	}
+	for subnetID, counts := range computedCounts {
+		maintained, ok := v.subnetCounts[subnetID]
+		if !ok || *maintained != *counts {
+			return fmt.Errorf("%w: maintained %+v for subnet %s but computed %+v",
+				errTotalsMismatch,
+				maintained,
+				subnetID,
+				*counts,
+			)
+		}
+
+		var numIndexed int
+		if validators, ok := v.validatorsByWeight[subnetID]; ok {
+			numIndexed = validators.Len()
+		}
+		if numIndexed != counts.validators {
+			return fmt.Errorf("%w: %d validators on subnet %s are indexed by weight but %d exist",
+				errTotalsMismatch,
+				numIndexed,
+				subnetID,
+				counts.validators,
+			)
+		}
+	}
+	for subnetID := range v.validatorsByWeight {
+		if _, ok := computedCounts[subnetID]; !ok {
+			return fmt.Errorf("%w: subnet %s without stakers is indexed by weight",
+				errTotalsMismatch,
+				subnetID,
+			)
+		}
+	}
+	for subnetID := range v.lastActivity {
+		if _, ok := computedCounts[subnetID]; !ok {
+			return fmt.Errorf("%w: activity recorded for subnet %s without stakers",
+				errTotalsMismatch,
+				subnetID,
+			)
+		}
+	}
+	return nil
 }
 
-func (v *baseStakers) PutValidator(staker *Staker) {
+// loadValidator adds [staker] as a validator without recording it in the
+// validator diffs.
+func (v *baseStakers) loadValidator(staker *Staker) {
 	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
+	if validator.validator != nil {
+		v.totals.removeValidator(validator.validator)
+		v.unindexValidator(validator.validator)
+		v.subPotentialReward(validator.validator)
+		// The replaced validator may be ordered differently.
+		v.stakers.Delete(validator.validator)
+	} else {
+		v.updateCounts(staker.SubnetID, 1, 0)
+	}
 	validator.validator = staker
-
-	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
-	validatorDiff.validatorStatus = added
-	validatorDiff.validator = staker
+	v.totals.addValidator(staker)
+	v.addPotentialReward(staker)
+	v.indexValidator(staker)
 
 	v.stakers.ReplaceOrInsert(staker)
 }
 
-func (v *baseStakers) DeleteValidator(staker *Staker) {
-	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-	validator.validator = nil
-	v.pruneValidator(staker.SubnetID, staker.NodeID)
+// cloneTree returns a copy of [tree], or nil if [tree] is nil.
+func cloneTree(tree *btree.BTreeG[*Staker]) *btree.BTreeG[*Staker] {
+	if tree == nil {
+		return nil
+	}
+	return tree.Clone()
+}
 
-	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
-	validatorDiff.validatorStatus = deleted
-	validatorDiff.validator = staker
+// updateCounts changes the number of validators and delegators on [subnetID]
+// by [validators] and [delegators] respectively.
+func (v *baseStakers) updateCounts(subnetID ids.ID, validators int, delegators int) {
+	counts, ok := v.subnetCounts[subnetID]
+	if !ok {
+		counts = &subnetStakerCounts{}
+		v.subnetCounts[subnetID] = counts
+	}
+	counts.validators += validators
+	counts.delegators += delegators
+	if counts.validators == 0 && counts.delegators == 0 {
+		delete(v.subnetCounts, subnetID)
+		delete(v.lastActivity, subnetID)
+	}
+	v.metrics.set(subnetID, *counts)
+}
 
-	v.stakers.Delete(staker)
+// addPotentialReward adds the potential reward of [staker] to the total of its
+// subnet. The staker must already be included in the counts of its subnet.
+func (v *baseStakers) addPotentialReward(staker *Staker) {
+	v.subnetCounts[staker.SubnetID].potentialReward.add(staker.PotentialReward)
 }
 
-func (v *baseStakers) GetDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID) iterator.Iterator[*Staker] {
-	subnetValidators, ok := v.validators[subnetID]
-	if !ok {
-		return iterator.Empty[*Staker]{}
+// subPotentialReward removes the potential reward of [staker] from the total
+// of its subnet. The staker must still be included in the counts of its
+// subnet.
+func (v *baseStakers) subPotentialReward(staker *Staker) {
+	v.subnetCounts[staker.SubnetID].potentialReward.sub(staker.PotentialReward)
+}
+
+// recordActivity marks the current timestamp as the most recent staker
+// activity on [subnetID] if it is later than the previously recorded activity.
+// Subnets without any stakers are not tracked.
+func (v *baseStakers) recordActivity(subnetID ids.ID) {
+	if _, ok := v.subnetCounts[subnetID]; !ok {
+		return
 	}
-	validator, ok := subnetValidators[nodeID]
+	if v.timestamp.After(v.lastActivity[subnetID]) {
+		v.lastActivity[subnetID] = v.timestamp
+	}
+}
+
+// indexValidator adds [staker] to the weight index of its subnet.
+func (v *baseStakers) indexValidator(staker *Staker) {
+	validators, ok := v.validatorsByWeight[staker.SubnetID]
 	if !ok {
-		return iterator.Empty[*Staker]{}
+		validators = btree.NewG(defaultTreeDegree, lessByWeight)
+		v.validatorsByWeight[staker.SubnetID] = validators
 	}
-	return iterator.FromTree(validator.delegators)
+	validators.ReplaceOrInsert(staker)
 }
 
-func (v *baseStakers) PutDelegator(staker *Staker) {
-	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-	if validator.delegators == nil {
-		validator.delegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
+// unindexValidator removes [staker] from the weight index of its subnet.
+func (v *baseStakers) unindexValidator(staker *Staker) {
+	validators, ok := v.validatorsByWeight[staker.SubnetID]
+	if !ok {
+		return
 	}
-	validator.delegators.ReplaceOrInsert(staker)
+	validators.Delete(staker)
+	if validators.Len() == 0 {
+		delete(v.validatorsByWeight, staker.SubnetID)
+	}
+}
 
-	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
-	if validatorDiff.addedDelegators == nil {
-		validatorDiff.addedDelegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
+// lessByWeight orders validators of the same subnet by their weight and then
+// by their NodeID.
+func lessByWeight(a, b *Staker) bool {
+	if a.Weight != b.Weight {
+		return a.Weight < b.Weight
 	}
-	validatorDiff.addedDelegators.ReplaceOrInsert(staker)
+	return a.NodeID.Compare(b.NodeID) < 0
+}
+
+// loadDelegator adds [staker] as a delegator without recording it in the
+// validator diffs.
+func (v *baseStakers) loadDelegator(staker *Staker) {
+	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
+	v.insertDelegator(validator, staker)
 
 	v.stakers.ReplaceOrInsert(staker)
 }
 
-func (v *baseStakers) DeleteDelegator(staker *Staker) {
-	validator := v.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-	if validator.delegators != nil {
-		validator.delegators.Delete(staker)
+// insertDelegator adds [staker] to the delegators of [validator] without
+// modifying [stakers] or the validator diffs.
+func (v *baseStakers) insertDelegator(validator *baseStaker, staker *Staker) {
+	if validator.delegators == nil {
+		validator.delegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
 	}
-	v.pruneValidator(staker.SubnetID, staker.NodeID)
-
-	validatorDiff := v.getOrCreateValidatorDiff(staker.SubnetID, staker.NodeID)
-	if validatorDiff.deletedDelegators == nil {
-		validatorDiff.deletedDelegators = make(map[ids.ID]*Staker)
+	if replaced, ok := validator.delegators.ReplaceOrInsert(staker); ok {
+		v.totals.removeDelegator(replaced)
+		v.subPotentialReward(replaced)
+	} else {
+		v.updateCounts(staker.SubnetID, 0, 1)
 	}
-	validatorDiff.deletedDelegators[staker.TxID] = staker
-
-	v.stakers.Delete(staker)
+	v.totals.addDelegator(staker)
+	v.addPotentialReward(staker)
 }
 
-func (v *baseStakers) GetStakerIterator() iterator.Iterator[*Staker] {
-	return iterator.FromTree(v.stakers)
+// reserve allocates the validator and diff maps of any subnets of [stakers]
+// that don't have them yet, sized to hold all of [stakers].
+func (v *baseStakers) reserve(stakers []*Staker) {
+	numStakers := make(map[ids.ID]int)
+	for _, staker := range stakers {
+		numStakers[staker.SubnetID]++
+	}
+	for subnetID, size := range numStakers {
+		if _, ok := v.validators[subnetID]; !ok {
+			v.validators[subnetID] = make(map[ids.NodeID]*baseStaker, size)
+		}
+		if _, ok := v.validatorDiffs[subnetID]; !ok {
+			v.validatorDiffs[subnetID] = make(map[ids.NodeID]*diffValidator, size)
+		}
+	}
 }
 
 func (v *baseStakers) getOrCreateValidator(subnetID ids.ID, nodeID ids.NodeID) *baseStaker {
@@ -254,6 +1906,145 @@ func (v *baseStakers) getOrCreateValidatorDiff(subnetID ids.ID, nodeID ids.NodeI
 	return validatorDiff
 }
 
+// subnetValidators returns the validators on [subnetID] in no particular
+// order.
+func (v *baseStakers) subnetValidators(subnetID ids.ID) []*Staker {
+	subnetValidators := v.validators[subnetID]
+	validators := make([]*Staker, 0, len(subnetValidators))
+	for _, validator := range subnetValidators {
+		if validator.validator != nil {
+			validators = append(validators, validator.validator)
+		}
+	}
+	return validators
+}
+
+// subnetStakers returns the validators and delegators on [subnetID] in no
+// particular order.
+func (v *baseStakers) subnetStakers(subnetID ids.ID) []*Staker {
+	var stakers []*Staker
+	for _, validator := range v.validators[subnetID] {
+		if validator.validator != nil {
+			stakers = append(stakers, validator.validator)
+		}
+		if validator.delegators != nil {
+			validator.delegators.Ascend(func(delegator *Staker) bool {
+				stakers = append(stakers, delegator)
+				return true
+			})
+		}
+	}
+	return stakers
+}
+
+// numValidatorsActiveAt returns the number of validators on [subnetID] whose
+// [StartTime, EndTime) contains [at].
+func (v *baseStakers) numValidatorsActiveAt(subnetID ids.ID, at time.Time) int {
+	var count int
+	for _, validator := range v.subnetValidators(subnetID) {
+		if !at.Before(validator.StartTime) && at.Before(validator.EndTime) {
+			count++
+		}
+	}
+	return count
+}
+
+// validatorWeightsDescending returns the weights of the validators on
+// [subnetID] from heaviest to lightest.
+func (v *baseStakers) validatorWeightsDescending(subnetID ids.ID) []uint64 {
+	weights := stakerWeights(v.subnetValidators(subnetID))
+	slices.SortFunc(weights, func(a, b uint64) int {
+		return cmp.Compare(b, a)
+	})
+	return weights
+}
+
+// stakerWeights returns the weights of [stakers].
+func stakerWeights(stakers []*Staker) []uint64 {
+	weights := make([]uint64, len(stakers))
+	for i, staker := range stakers {
+		weights[i] = staker.Weight
+	}
+	return weights
+}
+
+// sumWeights returns the sum of [weights]. If the sum exceeds a uint64,
+// [safemath.ErrOverflow] is returned.
+func sumWeights(weights []uint64) (uint64, error) {
+	var (
+		totalWeight uint64
+		err         error
+	)
+	for _, weight := range weights {
+		totalWeight, err = safemath.Add(totalWeight, weight)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return totalWeight, nil
+}
+
+// compareStakers orders stakers by their removal from the staker set.
+func compareStakers(a, b *Staker) int {
+	switch {
+	case a.Less(b):
+		return -1
+	case b.Less(a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// herfindahlIndex returns the sum of the squared shares of [totalWeight] held
+// by each of [weights].
+func herfindahlIndex(weights []uint64, totalWeight uint64) float64 {
+	var index float64
+	for _, weight := range weights {
+		share := float64(weight) / float64(totalWeight)
+		index += share * share
+	}
+	return index
+}
+
+// StakerEventType describes the mutation that a [StakerEvent] reports.
+type StakerEventType byte
+
+const (
+	ValidatorAdded StakerEventType = iota
+	ValidatorDeleted
+	DelegatorAdded
+	DelegatorDeleted
+)
+
+// StakerEvent reports a mutation that was applied to a staker set.
+type StakerEvent struct {
+	Type   StakerEventType
+	Staker *Staker
+}
+
+// RewardEvent describes the reward that is expected to be paid out to a
+// validator once it is removed from the staker set.
+type RewardEvent struct {
+	EndTime         time.Time
+	NodeID          ids.NodeID
+	PotentialReward uint64
+}
+
+// SubnetStake is the combined weight of the validators of a subnet.
+type SubnetStake struct {
+	SubnetID ids.ID
+	Weight   uint64
+}
+
+// CanonicalValidator is the minimal description of a validator that is needed
+// to verify warp messages.
+type CanonicalValidator struct {
+	NodeID    ids.NodeID
+	Weight    uint64
+	PublicKey *bls.PublicKey
+}
+
 type diffStakers struct {
 	// subnetID --> nodeID --> diff for that validator
 	validatorDiffs map[ids.ID]map[ids.NodeID]*diffValidator
@@ -271,6 +2062,17 @@ type diffValidator struct {
 
 	addedDelegators   *btree.BTreeG[*Staker]
 	deletedDelegators map[ids.ID]*Staker
+
+	// weightDiff is the net change in weight of this validator, including its
+	// delegators, caused by this diff. It is only maintained by diffStakers.
+	weightDiff ValidatorWeightDiff
+}
+
+// addWeight records a change of [amount] in the weight of the validator.
+func (v *diffValidator) addWeight(negative bool, amount uint64) {
+	// Invariant: The total stake is bounded by the supply, so the weight diff
+	// can't overflow.
+	_ = v.weightDiff.Add(negative, amount)
 }
 
 // GetValidator attempts to fetch the validator with the given subnetID and
@@ -293,6 +2095,166 @@ func (s *diffStakers) GetValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker,
 	return nil, validatorDiff.validatorStatus
 }
 
+// GetEffectiveValidators returns the validators on [subnetID] with [nodeIDs]
+// as seen after applying [d] on top of [base]. Validators that don't exist or
+// are deleted by [d] are omitted.
+func GetEffectiveValidators(
+	base *baseStakers,
+	d *diffStakers,
+	subnetID ids.ID,
+	nodeIDs []ids.NodeID,
+) map[ids.NodeID]*Staker {
+	validators := make(map[ids.NodeID]*Staker, len(nodeIDs))
+	for _, nodeID := range nodeIDs {
+		validator, status := d.GetValidator(subnetID, nodeID)
+		switch status {
+		case added:
+			validators[nodeID] = validator
+		case unmodified:
+			validator, err := base.GetValidator(subnetID, nodeID)
+			if err == nil {
+				validators[nodeID] = validator
+			}
+		}
+	}
+	return validators
+}
+
+// StakeAtDiff returns the total weight of the stakers on [subnetID] after
+// applying the first [upTo] of [diffs] on top of [base]. Neither [base] nor
+// [diffs] are modified.
+func StakeAtDiff(base *baseStakers, diffs []*diffStakers, upTo int, subnetID ids.ID) (uint64, error) {
+	if upTo < 0 || upTo > len(diffs) {
+		return 0, fmt.Errorf("%w: %d not in [0, %d]", errInvalidDiffIndex, upTo, len(diffs))
+	}
+
+	stake, err := sumWeights(stakerWeights(base.subnetStakers(subnetID)))
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range diffs[:upTo] {
+		// Increases are applied before decreases so that the result doesn't
+		// depend on the order of the validators.
+		var increase, decrease uint64
+		for _, weightDiff := range d.WeightDiffs()[subnetID] {
+			if weightDiff.Decrease {
+				decrease, err = safemath.Add(decrease, weightDiff.Amount)
+			} else {
+				increase, err = safemath.Add(increase, weightDiff.Amount)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		stake, err = safemath.Add(stake, increase)
+		if err != nil {
+			return 0, err
+		}
+		stake, err = safemath.Sub(stake, decrease)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return stake, nil
+}
+
+// DiffBetween returns a diff that, when applied to [oldStakers], results in the
+// same stakers as [newStakers]. A diff can not delete a validator and then add
+// a different one for the same subnet and node, so a validator that is replaced
+// is only recorded as an addition, which replaces the old validator when the
+// diff is applied.
+func DiffBetween(oldStakers, newStakers *baseStakers) *diffStakers {
+	diff := &diffStakers{}
+	for subnetID, oldValidators := range oldStakers.validators {
+		for nodeID, oldValidator := range oldValidators {
+			var newValidator *baseStaker
+			if newValidators, ok := newStakers.validators[subnetID]; ok {
+				newValidator = newValidators[nodeID]
+			}
+			if newValidator == nil {
+				newValidator = &baseStaker{}
+			}
+			diffValidatorBetween(diff, oldValidator, newValidator)
+		}
+	}
+	for subnetID, newValidators := range newStakers.validators {
+		oldValidators := oldStakers.validators[subnetID]
+		for nodeID, newValidator := range newValidators {
+			if _, ok := oldValidators[nodeID]; ok {
+				continue
+			}
+			diffValidatorBetween(diff, &baseStaker{}, newValidator)
+		}
+	}
+	return diff
+}
+
+// diffValidatorBetween records in [diff] the changes required to turn
+// [oldValidator] into [newValidator].
+func diffValidatorBetween(diff *diffStakers, oldValidator, newValidator *baseStaker) {
+	switch oldStaker, newStaker := oldValidator.validator, newValidator.validator; {
+	case oldStaker == nil && newStaker == nil:
+	case newStaker == nil:
+		diff.DeleteValidator(oldStaker)
+	case oldStaker == nil || !stakersEqual(oldStaker, newStaker):
+		// Every validator is only visited once, so it can't have been deleted
+		// from the diff.
+		_ = diff.PutValidator(newStaker)
+	}
+
+	// Delegators are identified by their position in the staker set. A
+	// delegator that is modified without moving is re-added, while one that is
+	// moved is deleted and added.
+	if oldValidator.delegators != nil {
+		oldValidator.delegators.Ascend(func(oldDelegator *Staker) bool {
+			if newValidator.delegators == nil || !newValidator.delegators.Has(oldDelegator) {
+				diff.DeleteDelegator(oldDelegator)
+			}
+			return true
+		})
+	}
+	if newValidator.delegators != nil {
+		newValidator.delegators.Ascend(func(newDelegator *Staker) bool {
+			if oldValidator.delegators != nil {
+				oldDelegator, ok := oldValidator.delegators.Get(newDelegator)
+				if ok && stakersEqual(oldDelegator, newDelegator) {
+					return true
+				}
+			}
+			diff.PutDelegator(newDelegator)
+			return true
+		})
+	}
+}
+
+// stakersEqual returns true if every field of [a] and [b] is equal. Times are
+// compared by the instant they represent and public keys by their compressed
+// bytes.
+func stakersEqual(a, b *Staker) bool {
+	return a.TxID == b.TxID &&
+		a.NodeID == b.NodeID &&
+		publicKeysEqual(a.PublicKey, b.PublicKey) &&
+		a.SubnetID == b.SubnetID &&
+		a.Weight == b.Weight &&
+		a.StartTime.Equal(b.StartTime) &&
+		a.EndTime.Equal(b.EndTime) &&
+		a.PotentialReward == b.PotentialReward &&
+		a.NextTime.Equal(b.NextTime) &&
+		a.Priority == b.Priority &&
+		a.Slashed == b.Slashed
+}
+
+func publicKeysEqual(a, b *bls.PublicKey) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return bytes.Equal(
+		bls.PublicKeyToCompressedBytes(a),
+		bls.PublicKeyToCompressedBytes(b),
+	)
+}
+
 func (s *diffStakers) PutValidator(staker *Staker) error {
 	validatorDiff := s.getOrCreateDiff(staker.SubnetID, staker.NodeID)
 	if validatorDiff.validatorStatus == deleted {
@@ -301,6 +2263,12 @@ func (s *diffStakers) PutValidator(staker *Staker) error {
 		return ErrAddingStakerAfterDeletion
 	}
 
+	if validatorDiff.validatorStatus == added {
+		// The previously added validator is being replaced.
+		validatorDiff.addWeight(true, validatorDiff.validator.Weight)
+	}
+	validatorDiff.addWeight(false, staker.Weight)
+
 	validatorDiff.validatorStatus = added
 	validatorDiff.validator = staker
 
@@ -318,8 +2286,15 @@ func (s *diffStakers) DeleteValidator(staker *Staker) {
 		// treat it as if it was never added.
 		validatorDiff.validatorStatus = unmodified
 		s.addedStakers.Delete(validatorDiff.validator)
+		validatorDiff.addWeight(true, validatorDiff.validator.Weight)
 		validatorDiff.validator = nil
 	} else {
+		if validatorDiff.validatorStatus == deleted {
+			// The validator was already deleted in this diff.
+			validatorDiff.addWeight(false, validatorDiff.validator.Weight)
+		}
+		validatorDiff.addWeight(true, staker.Weight)
+
 		validatorDiff.validatorStatus = deleted
 		validatorDiff.validator = staker
 		if s.deletedStakers == nil {
@@ -329,6 +2304,30 @@ func (s *diffStakers) DeleteValidator(staker *Staker) {
 	}
 }
 
+// WeightDiffs returns the net change in weight of each validator, including
+// its delegators, caused by this diff. Validators whose weight is unchanged
+// are omitted. The returned diffs match the weight diffs written to disk when
+// this diff is applied.
+func (s *diffStakers) WeightDiffs() map[ids.ID]map[ids.NodeID]*ValidatorWeightDiff {
+	weightDiffs := make(map[ids.ID]map[ids.NodeID]*ValidatorWeightDiff)
+	for subnetID, subnetValidatorDiffs := range s.validatorDiffs {
+		for nodeID, validatorDiff := range subnetValidatorDiffs {
+			if validatorDiff.weightDiff.Amount == 0 {
+				continue
+			}
+
+			subnetWeightDiffs, ok := weightDiffs[subnetID]
+			if !ok {
+				subnetWeightDiffs = make(map[ids.NodeID]*ValidatorWeightDiff)
+				weightDiffs[subnetID] = subnetWeightDiffs
+			}
+			weightDiff := validatorDiff.weightDiff
+			subnetWeightDiffs[nodeID] = &weightDiff
+		}
+	}
+	return weightDiffs
+}
+
 func (s *diffStakers) GetDelegatorIterator(
 	parentIterator iterator.Iterator[*Staker],
 	subnetID ids.ID,
@@ -358,12 +2357,35 @@ func (s *diffStakers) GetDelegatorIterator(
 	)
 }
 
+// NumDelegators returns the number of delegators of the validator on
+// [subnetID] with [nodeID], given that the parent state reports
+// [parentNumDelegators].
+func (s *diffStakers) NumDelegators(parentNumDelegators int, subnetID ids.ID, nodeID ids.NodeID) int {
+	subnetValidatorDiffs, ok := s.validatorDiffs[subnetID]
+	if !ok {
+		return parentNumDelegators
+	}
+	validatorDiff, ok := subnetValidatorDiffs[nodeID]
+	if !ok {
+		return parentNumDelegators
+	}
+
+	numDelegators := parentNumDelegators - len(validatorDiff.deletedDelegators)
+	if validatorDiff.addedDelegators != nil {
+		numDelegators += validatorDiff.addedDelegators.Len()
+	}
+	return numDelegators
+}
+
 func (s *diffStakers) PutDelegator(staker *Staker) {
 	validatorDiff := s.getOrCreateDiff(staker.SubnetID, staker.NodeID)
 	if validatorDiff.addedDelegators == nil {
 		validatorDiff.addedDelegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
 	}
-	validatorDiff.addedDelegators.ReplaceOrInsert(staker)
+	if replaced, ok := validatorDiff.addedDelegators.ReplaceOrInsert(staker); ok {
+		validatorDiff.addWeight(true, replaced.Weight)
+	}
+	validatorDiff.addWeight(false, staker.Weight)
 
 	if s.addedStakers == nil {
 		s.addedStakers = btree.NewG(defaultTreeDegree, (*Staker).Less)
@@ -376,6 +2398,10 @@ func (s *diffStakers) DeleteDelegator(staker *Staker) {
 	if validatorDiff.deletedDelegators == nil {
 		validatorDiff.deletedDelegators = make(map[ids.ID]*Staker)
 	}
+	if previous, ok := validatorDiff.deletedDelegators[staker.TxID]; ok {
+		validatorDiff.addWeight(false, previous.Weight)
+	}
+	validatorDiff.addWeight(true, staker.Weight)
 	validatorDiff.deletedDelegators[staker.TxID] = staker
 
 	if s.deletedStakers == nil {
@@ -398,6 +2424,195 @@ func (s *diffStakers) GetStakerIterator(parentIterator iterator.Iterator[*Staker
 	)
 }
 
+// GetStakerReverseIterator returns the stakers in the reverse order of
+// [GetStakerIterator]. [parentIterator] must return stakers in descending
+// order.
+func (s *diffStakers) GetStakerReverseIterator(parentIterator iterator.Iterator[*Staker]) iterator.Iterator[*Staker] {
+	return iterator.Filter(
+		iterator.Merge(
+			func(a, b *Staker) bool {
+				return b.Less(a)
+			},
+			parentIterator,
+			iterator.FromTreeReverse(s.addedStakers),
+		),
+		func(staker *Staker) bool {
+			_, ok := s.deletedStakers[staker.TxID]
+			return ok
+		},
+	)
+}
+
+// layeredStaker is a staker along with the index of the diff layer that added
+// it. Stakers from the parent iterator have a layer of -1.
+type layeredStaker struct {
+	staker *Staker
+	layer  int
+}
+
+// GetLayeredStakerIterator returns the stakers that result from applying
+// [diffs], ordered from the oldest to the newest, on top of [parentIterator].
+//
+// This is equivalent to nesting [diffStakers.GetStakerIterator] calls, but the
+// layers are merged in a single pass so the cost of each call to Next grows
+// logarithmically, rather than linearly, with the number of layers. If
+// multiple layers contain a staker with the same TxID, only the staker from
+// the newest layer is returned.
+func GetLayeredStakerIterator(
+	parentIterator iterator.Iterator[*Staker],
+	diffs []*diffStakers,
+) iterator.Iterator[*Staker] {
+	// newestLayer maps each TxID modified by [diffs] to the newest layer that
+	// added or deleted it.
+	var (
+		newestLayer = make(map[ids.ID]int)
+		iterators   = make([]iterator.Iterator[layeredStaker], 0, len(diffs)+1)
+	)
+	iterators = append(iterators, withLayer(parentIterator, -1))
+	for layer, d := range diffs {
+		if d.addedStakers != nil {
+			d.addedStakers.Ascend(func(staker *Staker) bool {
+				newestLayer[staker.TxID] = layer
+				return true
+			})
+			iterators = append(iterators, withLayer(iterator.FromTree(d.addedStakers), layer))
+		}
+		for txID := range d.deletedStakers {
+			newestLayer[txID] = layer
+		}
+	}
+
+	return iterator.Map(
+		iterator.Filter(
+			iterator.Merge(
+				func(a, b layeredStaker) bool {
+					return a.staker.Less(b.staker)
+				},
+				iterators...,
+			),
+			func(s layeredStaker) bool {
+				layer, ok := newestLayer[s.staker.TxID]
+				if !ok {
+					return false
+				}
+				if layer != s.layer {
+					// The staker was replaced or deleted by a newer layer.
+					return true
+				}
+				_, deleted := diffs[layer].deletedStakers[s.staker.TxID]
+				return deleted
+			},
+		),
+		func(s layeredStaker) *Staker {
+			return s.staker
+		},
+	)
+}
+
+func withLayer(it iterator.Iterator[*Staker], layer int) iterator.Iterator[layeredStaker] {
+	return iterator.Map(it, func(staker *Staker) layeredStaker {
+		return layeredStaker{
+			staker: staker,
+			layer:  layer,
+		}
+	})
+}
+
+// ApplyStreaming applies the diff to [base], sending an event to [out] for
+// every mutation that is applied. [out] is not closed once the diff has been
+// applied.
+func (s *diffStakers) ApplyStreaming(base *baseStakers, out chan<- StakerEvent) error {
+	s.apply(base, func(event StakerEvent) {
+		out <- event
+	})
+	return nil
+}
+
+// ApplyWithBatchedEvents applies the diff to [base] and then calls [fn] once
+// with the events of every mutation that was applied. The error returned by
+// [fn] is returned.
+func (s *diffStakers) ApplyWithBatchedEvents(base *baseStakers, fn func([]StakerEvent) error) error {
+	var events []StakerEvent
+	s.apply(base, func(event StakerEvent) {
+		events = append(events, event)
+	})
+	return fn(events)
+}
+
+// apply applies the diff to [base] and calls [onEvent] after each mutation.
+// Mutations are applied in order of the mutated stakers so that the events are
+// emitted deterministically.
+func (s *diffStakers) apply(base *baseStakers, onEvent func(StakerEvent)) {
+	for _, event := range s.events() {
+		switch event.Type {
+		case ValidatorAdded:
+			base.PutValidator(event.Staker)
+		case ValidatorDeleted:
+			base.DeleteValidator(event.Staker)
+		case DelegatorAdded:
+			base.PutDelegator(event.Staker)
+		case DelegatorDeleted:
+			base.DeleteDelegator(event.Staker)
+		}
+		onEvent(event)
+	}
+}
+
+// events returns the mutations of the diff in order of the mutated stakers. If
+// a delegator is both added and deleted, the addition is ordered first, as the
+// deletion takes precedence in the diff.
+func (s *diffStakers) events() []StakerEvent {
+	var events []StakerEvent
+	for _, subnetValidatorDiffs := range s.validatorDiffs {
+		for _, validatorDiff := range subnetValidatorDiffs {
+			switch validatorDiff.validatorStatus {
+			case added:
+				events = append(events, StakerEvent{
+					Type:   ValidatorAdded,
+					Staker: validatorDiff.validator,
+				})
+			case deleted:
+				events = append(events, StakerEvent{
+					Type:   ValidatorDeleted,
+					Staker: validatorDiff.validator,
+				})
+			}
+
+			if validatorDiff.addedDelegators != nil {
+				validatorDiff.addedDelegators.Ascend(func(delegator *Staker) bool {
+					events = append(events, StakerEvent{
+						Type:   DelegatorAdded,
+						Staker: delegator,
+					})
+					return true
+				})
+			}
+
+			for _, delegator := range validatorDiff.deletedDelegators {
+				events = append(events, StakerEvent{
+					Type:   DelegatorDeleted,
+					Staker: delegator,
+				})
+			}
+		}
+	}
+	slices.SortFunc(events, func(a, b StakerEvent) int {
+		if c := compareStakers(a.Staker, b.Staker); c != 0 {
+			return c
+		}
+		// Only a delegator can be both added and deleted by the same diff.
+		switch {
+		case a.Type == b.Type:
+			return 0
+		case a.Type == DelegatorAdded:
+			return -1
+		default:
+			return 1
+		}
+	})
+	return events
+}
+
 func (s *diffStakers) getOrCreateDiff(subnetID ids.ID, nodeID ids.NodeID) *diffValidator {
 	if s.validatorDiffs == nil {
 		s.validatorDiffs = make(map[ids.ID]map[ids.NodeID]*diffValidator)
@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+const benchmarkNumStakers = 100_000
+
+// newBenchmarkStakers returns [benchmarkNumStakers] validators spread across a
+// handful of subnets along with one delegator per validator.
+func newBenchmarkStakers() ([]*Staker, []*Staker) {
+	subnetIDs := make([]ids.ID, 8)
+	for i := range subnetIDs {
+		subnetIDs[i] = ids.GenerateTestID()
+	}
+
+	var (
+		validators = make([]*Staker, benchmarkNumStakers)
+		delegators = make([]*Staker, benchmarkNumStakers)
+	)
+	for i := range validators {
+		validator := newTestStaker()
+		validator.SubnetID = subnetIDs[i%len(subnetIDs)]
+		validators[i] = validator
+
+		delegator := newTestStaker()
+		delegator.SubnetID = validator.SubnetID
+		delegator.NodeID = validator.NodeID
+		delegators[i] = delegator
+	}
+	return validators, delegators
+}
+
+func BenchmarkBaseStakersPut(b *testing.B) {
+	validators, delegators := newBenchmarkStakers()
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			v := newBaseStakers()
+			for _, validator := range validators {
+				v.PutValidator(validator)
+			}
+			for _, delegator := range delegators {
+				v.PutDelegator(delegator)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			v := newBaseStakers()
+			v.PutValidators(validators)
+			v.PutDelegators(delegators)
+		}
+	})
+}
+
+func BenchmarkGetLayeredStakerIterator(b *testing.B) {
+	validators, delegators := newBenchmarkStakers()
+
+	base := newBaseStakers()
+	base.PutValidators(validators)
+
+	for _, numLayers := range []int{1, 10, 100} {
+		// Each layer adds a delegator and deletes a validator.
+		diffs := make([]*diffStakers, numLayers)
+		for i := range diffs {
+			d := &diffStakers{}
+			d.PutDelegator(delegators[i])
+			d.DeleteValidator(validators[i])
+			diffs[i] = d
+		}
+
+		b.Run(fmt.Sprintf("nested_%d", numLayers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				it := base.GetStakerIterator()
+				for _, d := range diffs {
+					it = d.GetStakerIterator(it)
+				}
+				for it.Next() {
+					_ = it.Value()
+				}
+				it.Release()
+			}
+		})
+		b.Run(fmt.Sprintf("layered_%d", numLayers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				it := GetLayeredStakerIterator(base.GetStakerIterator(), diffs)
+				for it.Next() {
+					_ = it.Value()
+				}
+				it.Release()
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const subnetIDLabel = "subnetID"
+
+// stakerMetrics reports the number of validators and delegators of a staker
+// set on each subnet. Only subnets with at least one staker are reported, so
+// the number of labels is bounded by the number of active subnets.
+//
+// A nil *stakerMetrics is valid and reports nothing.
+type stakerMetrics struct {
+	validators *prometheus.GaugeVec
+	delegators *prometheus.GaugeVec
+}
+
+// newStakerMetrics registers the gauges of a staker set. The gauges are named
+// [prefix]_validators and [prefix]_delegators and [kind] describes the staker
+// set in their help text.
+func newStakerMetrics(
+	registerer prometheus.Registerer,
+	prefix string,
+	kind string,
+) (*stakerMetrics, error) {
+	m := &stakerMetrics{
+		validators: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: prefix + "_validators",
+				Help: "Number of " + kind + " validators of the subnet",
+			},
+			[]string{subnetIDLabel},
+		),
+		delegators: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: prefix + "_delegators",
+				Help: "Number of " + kind + " delegators of the subnet",
+			},
+			[]string{subnetIDLabel},
+		),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.validators),
+		registerer.Register(m.delegators),
+	)
+	return m, errs.Err
+}
+
+// reset removes all of the reported subnets.
+func (m *stakerMetrics) reset() {
+	if m == nil {
+		return
+	}
+
+	m.validators.Reset()
+	m.delegators.Reset()
+}
+
+// set reports [counts] as the number of stakers on [subnetID].
+func (m *stakerMetrics) set(subnetID ids.ID, counts subnetStakerCounts) {
+	if m == nil {
+		return
+	}
+
+	label := subnetID.String()
+	if counts.validators == 0 && counts.delegators == 0 {
+		m.validators.DeleteLabelValues(label)
+		m.delegators.DeleteLabelValues(label)
+		return
+	}
+	m.validators.WithLabelValues(label).Set(float64(counts.validators))
+	m.delegators.WithLabelValues(label).Set(float64(counts.delegators))
+}
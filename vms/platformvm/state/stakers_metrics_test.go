@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStakerMetrics(t *testing.T) {
+	require := require.New(t)
+
+	m, err := newStakerMetrics(prometheus.NewRegistry(), "stakers", "current")
+	require.NoError(err)
+
+	staker := newTestStaker()
+	delegator := newTestStaker()
+
+	v := newMeteredBaseStakers(m)
+
+	v.PutDelegator(delegator)
+	requireStakerMetrics(t, m, map[string][2]float64{
+		delegator.SubnetID.String(): {0, 1},
+	})
+
+	v.PutValidator(staker)
+	requireStakerMetrics(t, m, map[string][2]float64{
+		delegator.SubnetID.String(): {0, 1},
+		staker.SubnetID.String():    {1, 0},
+	})
+
+	// Replacing a validator must not change the count.
+	v.PutValidator(staker)
+	requireStakerMetrics(t, m, map[string][2]float64{
+		delegator.SubnetID.String(): {0, 1},
+		staker.SubnetID.String():    {1, 0},
+	})
+
+	// Subnets without stakers must not be reported.
+	v.DeleteDelegator(delegator)
+	requireStakerMetrics(t, m, map[string][2]float64{
+		staker.SubnetID.String(): {1, 0},
+	})
+
+	v.DeleteValidator(staker)
+	requireStakerMetrics(t, m, nil)
+}
+
+func TestStakerMetricsApply(t *testing.T) {
+	require := require.New(t)
+
+	m, err := newStakerMetrics(prometheus.NewRegistry(), "stakers", "current")
+	require.NoError(err)
+
+	var (
+		baseValidator  = newTestStaker()
+		addedValidator = newTestStaker()
+		addedDelegator = newTestStaker()
+	)
+	addedDelegator.SubnetID = addedValidator.SubnetID
+	addedDelegator.NodeID = addedValidator.NodeID
+
+	v := newMeteredBaseStakers(m)
+	v.PutValidator(baseValidator)
+
+	d := diffStakers{}
+	d.DeleteValidator(baseValidator)
+	require.NoError(d.PutValidator(addedValidator))
+	d.PutDelegator(addedDelegator)
+	require.NoError(d.ApplyWithBatchedEvents(v, func([]StakerEvent) error {
+		return nil
+	}))
+
+	requireStakerMetrics(t, m, map[string][2]float64{
+		addedValidator.SubnetID.String(): {1, 1},
+	})
+
+	// Replacing the metered stakers must remove the previously reported
+	// subnets.
+	_ = newMeteredBaseStakers(m)
+	requireStakerMetrics(t, m, nil)
+}
+
+func TestPendingStakerMetrics(t *testing.T) {
+	require := require.New(t)
+
+	registry := prometheus.NewRegistry()
+	current, err := newStakerMetrics(registry, "stakers", "current")
+	require.NoError(err)
+	pending, err := newStakerMetrics(registry, "pending_stakers", "pending")
+	require.NoError(err)
+
+	staker := newTestStaker()
+	currentStakers := newMeteredBaseStakers(current)
+	pendingStakers := newMeteredBaseStakers(pending)
+
+	pendingStakers.PutValidator(staker)
+	requireStakerMetrics(t, current, nil)
+	requireStakerMetrics(t, pending, map[string][2]float64{
+		staker.SubnetID.String(): {1, 0},
+	})
+
+	// Promoting the staker must move it between the reported sets.
+	pendingStakers.DeleteValidator(staker)
+	currentStakers.PutValidator(staker)
+	requireStakerMetrics(t, current, map[string][2]float64{
+		staker.SubnetID.String(): {1, 0},
+	})
+	requireStakerMetrics(t, pending, nil)
+}
+
+// requireStakerMetrics asserts that [m] reports exactly the subnets in
+// [expected], which maps each subnetID to its number of validators and
+// delegators.
+func requireStakerMetrics(t *testing.T, m *stakerMetrics, expected map[string][2]float64) {
+	require := require.New(t)
+
+	t.Helper()
+
+	require.Equal(len(expected), testutil.CollectAndCount(m.validators))
+	require.Equal(len(expected), testutil.CollectAndCount(m.delegators))
+	for subnetID, counts := range expected {
+		require.Equal(counts[0], testutil.ToFloat64(m.validators.WithLabelValues(subnetID)))
+		require.Equal(counts[1], testutil.ToFloat64(m.delegators.WithLabelValues(subnetID)))
+	}
+}
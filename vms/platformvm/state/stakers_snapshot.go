@@ -0,0 +1,41 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"github.com/google/btree"
+
+	"github.com/ava-labs/avalanchego/utils/iterator"
+)
+
+// Snapshot returns an immutable view of the staker set. The snapshot shares its
+// underlying tree with the staker set using copy-on-write, so taking a snapshot
+// is cheap and subsequent modifications of the staker set are not reflected in
+// the snapshot.
+//
+// Cloning the tree modifies the staker set, so Snapshot must be synchronized
+// with any other access of the staker set. The returned snapshot doesn't
+// require any synchronization.
+func (v *baseStakers) Snapshot() *stakersSnapshot {
+	return &stakersSnapshot{
+		stakers: v.stakers.Clone(),
+	}
+}
+
+// stakersSnapshot is an immutable point-in-time view of a staker set. It is
+// safe to read from multiple goroutines.
+type stakersSnapshot struct {
+	stakers *btree.BTreeG[*Staker]
+}
+
+// Len returns the number of stakers in the snapshot.
+func (s *stakersSnapshot) Len() int {
+	return s.stakers.Len()
+}
+
+// GetStakerIterator returns the stakers in the snapshot in order of their
+// removal from the staker set.
+func (s *stakersSnapshot) GetStakerIterator() iterator.Iterator[*Staker] {
+	return iterator.FromTree(s.stakers)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/utils/iterator"
+)
+
+func TestBaseStakersSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	v := newBaseStakers()
+
+	staker := newTestStaker()
+	v.PutValidator(staker)
+
+	snapshot := v.Snapshot()
+
+	v.DeleteValidator(staker)
+	v.PutValidator(newTestStaker())
+
+	require.Equal(1, snapshot.Len())
+	assertIteratorsEqual(t, iterator.FromSlice(staker), snapshot.GetStakerIterator())
+}
+
+func TestStateSnapshotCurrentStakersConcurrentWrites(t *testing.T) {
+	require := require.New(t)
+
+	state := newTestState(t, memdb.New())
+
+	const numInitialStakers = 100
+	initialStakers := make([]*Staker, numInitialStakers)
+	for i := range initialStakers {
+		initialStakers[i] = newTestStaker()
+		require.NoError(state.PutCurrentValidator(initialStakers[i]))
+	}
+	expectedLen := state.currentStakers.stakers.Len()
+
+	snapshot := state.SnapshotCurrentStakers()
+
+	var (
+		wg       sync.WaitGroup
+		writeErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for _, staker := range initialStakers {
+			state.DeleteCurrentValidator(staker)
+			if err := state.PutCurrentValidator(newTestStaker()); err != nil {
+				writeErr = err
+				return
+			}
+			_ = state.SnapshotCurrentStakers()
+		}
+	}()
+
+	// The snapshot must be readable while the state is being modified.
+	const numReaders = 4
+	numStakers := make([]int, numReaders)
+	for i := range numStakers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			it := snapshot.GetStakerIterator()
+			defer it.Release()
+
+			for it.Next() {
+				numStakers[i]++
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.NoError(writeErr)
+
+	for _, n := range numStakers {
+		require.Equal(expectedLen, n)
+	}
+	require.Equal(expectedLen, snapshot.Len())
+}
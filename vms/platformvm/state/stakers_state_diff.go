@@ -0,0 +1,110 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var errMissingStaker = errors.New("missing staker")
+
+// stateDiff is the serialized form of the stakers that must be removed from,
+// and then added to, a [baseStakers] to transition it between two states.
+type stateDiff struct {
+	Removed []ids.ID       `v0:"true"`
+	Added   []stakerSchema `v0:"true"`
+}
+
+// EncodeStateDiff returns the serialized set of staker changes that transforms
+// [oldState] into [newState]. Stakers are identified by their TxID. A staker
+// whose serialized form differs between the two states is encoded as a removal
+// followed by an addition. Like the persisted state, the diff doesn't include
+// whether a staker is slashed.
+func EncodeStateDiff(oldState, newState *baseStakers) ([]byte, error) {
+	oldStakers := stakersByTxID(oldState)
+
+	var diff stateDiff
+	for _, staker := range stakersInOrder(newState) {
+		newSchema := newStakerSchema(staker)
+		if oldStaker, ok := oldStakers[staker.TxID]; ok {
+			delete(oldStakers, staker.TxID)
+
+			oldSchema := newStakerSchema(oldStaker)
+			if oldSchema.equal(&newSchema) {
+				continue
+			}
+			diff.Removed = append(diff.Removed, staker.TxID)
+		}
+		diff.Added = append(diff.Added, newSchema)
+	}
+	for _, staker := range stakersInOrder(oldState) {
+		if _, ok := oldStakers[staker.TxID]; ok {
+			diff.Removed = append(diff.Removed, staker.TxID)
+		}
+	}
+	return MetadataCodec.Marshal(SchemaVersion, &diff)
+}
+
+// ApplyStateDiff applies the staker changes encoded by [EncodeStateDiff] to
+// [base].
+func ApplyStateDiff(base *baseStakers, diffBytes []byte) error {
+	var diff stateDiff
+	version, err := MetadataCodec.Unmarshal(diffBytes, &diff)
+	if err != nil {
+		return err
+	}
+	if version != SchemaVersion {
+		return fmt.Errorf("%w: expected %d but got %d",
+			errUnexpectedSchemaVersion,
+			SchemaVersion,
+			version,
+		)
+	}
+
+	stakers := stakersByTxID(base)
+	for _, txID := range diff.Removed {
+		staker, ok := stakers[txID]
+		if !ok {
+			return fmt.Errorf("%w: %s", errMissingStaker, txID)
+		}
+		if staker.Priority.IsValidator() {
+			base.DeleteValidator(staker)
+		} else {
+			base.DeleteDelegator(staker)
+		}
+	}
+	for _, schema := range diff.Added {
+		staker, err := schema.staker()
+		if err != nil {
+			return err
+		}
+		if staker.Priority.IsValidator() {
+			base.PutValidator(staker)
+		} else {
+			base.PutDelegator(staker)
+		}
+	}
+	return nil
+}
+
+func stakersInOrder(v *baseStakers) []*Staker {
+	stakers := make([]*Staker, 0, v.stakers.Len())
+	v.stakers.Ascend(func(staker *Staker) bool {
+		stakers = append(stakers, staker)
+		return true
+	})
+	return stakers
+}
+
+func stakersByTxID(v *baseStakers) map[ids.ID]*Staker {
+	stakers := make(map[ids.ID]*Staker, v.stakers.Len())
+	v.stakers.Ascend(func(staker *Staker) bool {
+		stakers[staker.TxID] = staker
+		return true
+	})
+	return stakers
+}
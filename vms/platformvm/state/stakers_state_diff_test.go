@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/iterator"
+)
+
+func TestStateDiffRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		unchanged = newTestValidator(ids.GenerateTestID(), 1)
+		removed   = newTestValidator(ids.GenerateTestID(), 2)
+		modified  = newTestValidator(ids.GenerateTestID(), 3)
+		delegator = newTestStaker()
+		added     = newTestValidator(ids.GenerateTestID(), 4)
+	)
+	delegator.SubnetID = unchanged.SubnetID
+	delegator.NodeID = unchanged.NodeID
+
+	var (
+		oldState = newBaseStakers()
+		newState = newBaseStakers()
+	)
+	for _, staker := range []*Staker{unchanged, removed, modified} {
+		oldState.PutValidator(staker)
+	}
+	oldState.PutDelegator(delegator)
+
+	modifiedCopy := *modified
+	modifiedCopy.Weight++
+	for _, staker := range []*Staker{unchanged, &modifiedCopy, added} {
+		newState.PutValidator(staker)
+	}
+
+	diffBytes, err := EncodeStateDiff(oldState, newState)
+	require.NoError(err)
+
+	require.NoError(ApplyStateDiff(oldState, diffBytes))
+	assertIteratorsEqual(t, newState.GetStakerIterator(), oldState.GetStakerIterator())
+	for _, staker := range []*Staker{unchanged, &modifiedCopy, added} {
+		validator, err := oldState.GetValidator(staker.SubnetID, staker.NodeID)
+		require.NoError(err)
+		require.Equal(staker, validator)
+	}
+	_, err = oldState.GetValidator(removed.SubnetID, removed.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+	assertIteratorsEqual(
+		t,
+		iterator.Empty[*Staker]{},
+		oldState.GetDelegatorIterator(delegator.SubnetID, delegator.NodeID),
+	)
+}
+
+func TestApplyStateDiffMissingStaker(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		oldState = newBaseStakers()
+		newState = newBaseStakers()
+	)
+	oldState.PutValidator(newTestValidator(ids.GenerateTestID(), 1))
+
+	diffBytes, err := EncodeStateDiff(oldState, newState)
+	require.NoError(err)
+
+	err = ApplyStateDiff(newBaseStakers(), diffBytes)
+	require.ErrorIs(err, errMissingStaker)
+}
+
+func TestEncodeStateDiffIgnoresSlashing(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		validator = newTestValidator(ids.GenerateTestID(), 1)
+		oldState  = newBaseStakers()
+		newState  = newBaseStakers()
+	)
+	oldState.PutValidator(validator)
+	newState.PutValidator(validator)
+	require.NoError(newState.SlashValidator(validator.SubnetID, validator.NodeID))
+
+	diffBytes, err := EncodeStateDiff(oldState, newState)
+	require.NoError(err)
+
+	var diff stateDiff
+	_, err = MetadataCodec.Unmarshal(diffBytes, &diff)
+	require.NoError(err)
+	require.Empty(diff.Removed)
+	require.Empty(diff.Added)
+}
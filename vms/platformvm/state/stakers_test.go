@@ -4,6 +4,9 @@
 package state
 
 import (
+	"errors"
+	"math"
+	"slices"
 	"testing"
 	"time"
 
@@ -11,11 +14,18 @@ import (
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/iterator"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/vms/platformvm/genesis/genesistest"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
 )
 
+const floatDelta = .00001
+
 func TestBaseStakersPruning(t *testing.T) {
 	require := require.New(t)
 	staker := newTestStaker()
@@ -67,6 +77,53 @@ func TestBaseStakersPruning(t *testing.T) {
 	require.Empty(v.validators)
 }
 
+func TestBaseStakersClone(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		validator = newTestStaker()
+		delegator = newTestStaker()
+		other     = newTestStaker()
+	)
+	delegator.SubnetID = validator.SubnetID
+	delegator.NodeID = validator.NodeID
+
+	v := newBaseStakers()
+	v.PutValidator(validator)
+	v.PutDelegator(delegator)
+	v.PutValidator(other)
+
+	expected := newBaseStakers()
+	expected.PutValidator(validator)
+	expected.PutDelegator(delegator)
+	expected.PutValidator(other)
+
+	clone := v.Clone()
+	requireBaseStakersEqual(t, v, clone)
+
+	// Deleting the validator in the clone must preserve its delegator in the
+	// clone and must not modify the source.
+	clone.DeleteValidator(validator)
+	_, err := clone.GetValidator(validator.SubnetID, validator.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(delegator),
+		clone.GetDelegatorIterator(validator.SubnetID, validator.NodeID),
+	)
+	requireBaseStakersEqual(t, expected, v)
+
+	// Pruning the validator in the clone must not modify the source.
+	clone.DeleteDelegator(delegator)
+	require.NotContains(clone.validators, validator.SubnetID)
+	requireBaseStakersEqual(t, expected, v)
+
+	// Modifying the source must not modify the clone.
+	v.DeleteValidator(other)
+	_, err = clone.GetValidator(other.SubnetID, other.NodeID)
+	require.NoError(err)
+}
+
 func TestBaseStakersValidator(t *testing.T) {
 	require := require.New(t)
 	staker := newTestStaker()
@@ -108,6 +165,116 @@ func TestBaseStakersValidator(t *testing.T) {
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, stakerIterator)
 }
 
+func TestBaseStakersGetValidatorsByWeight(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	validatorIterator := v.GetValidatorsByWeight(subnetID, 0, math.MaxUint64)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, validatorIterator)
+
+	var (
+		light     = newTestValidator(subnetID, 1)
+		medium    = newTestValidator(subnetID, 5)
+		tiedA     = newTestValidator(subnetID, 10)
+		tiedB     = newTestValidator(subnetID, 10)
+		heavy     = newTestValidator(subnetID, 100)
+		otherNet  = newTestValidator(ids.GenerateTestID(), 5)
+		delegator = newTestStaker()
+	)
+	// Equal weights are ordered by NodeID.
+	if tiedB.NodeID.Compare(tiedA.NodeID) < 0 {
+		tiedA, tiedB = tiedB, tiedA
+	}
+	for _, validator := range []*Staker{heavy, tiedB, light, tiedA, medium, otherNet} {
+		v.PutValidator(validator)
+	}
+	delegator.SubnetID = subnetID
+	delegator.NodeID = light.NodeID
+	delegator.Weight = 5
+	v.PutDelegator(delegator)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 0, math.MaxUint64)
+	assertIteratorsEqual(t, iterator.FromSlice(light, medium, tiedA, tiedB, heavy), validatorIterator)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 5, 10)
+	assertIteratorsEqual(t, iterator.FromSlice(medium, tiedA, tiedB), validatorIterator)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 11, 99)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, validatorIterator)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 10, 5)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, validatorIterator)
+
+	// Replacing a validator must update its position in the index.
+	replacement := *light
+	replacement.Weight = 1000
+	v.PutValidator(&replacement)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 0, math.MaxUint64)
+	assertIteratorsEqual(t, iterator.FromSlice(medium, tiedA, tiedB, heavy, &replacement), validatorIterator)
+
+	// Slashed validators must be returned with their updated state.
+	require.NoError(v.SlashValidator(subnetID, heavy.NodeID))
+	slashed, err := v.GetValidator(subnetID, heavy.NodeID)
+	require.NoError(err)
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 100, 100)
+	assertIteratorsEqual(t, iterator.FromSlice(slashed), validatorIterator)
+
+	for _, validator := range []*Staker{medium, tiedA, tiedB, slashed, &replacement} {
+		v.DeleteValidator(validator)
+	}
+
+	validatorIterator = v.GetValidatorsByWeight(subnetID, 0, math.MaxUint64)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, validatorIterator)
+	require.NotContains(v.validatorsByWeight, subnetID)
+}
+
+func TestBaseStakersReverseIterator(t *testing.T) {
+	v := newBaseStakers()
+
+	stakerIterator := v.GetStakerReverseIterator()
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, stakerIterator)
+
+	var (
+		baseTime = time.Unix(1000, 0)
+		stakers  = newReverseIteratorTestStakers(baseTime)
+	)
+	v.PutValidator(stakers.firstValidator)
+	v.PutDelegator(stakers.tiedDelegator)
+	v.PutValidator(stakers.tiedValidator)
+	v.PutValidator(stakers.lastValidator)
+
+	// Stakers sharing a NextTime are ordered by priority, so the validator
+	// that is removed after its delegator must be returned first.
+	stakerIterator = v.GetStakerReverseIterator()
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(
+			stakers.lastValidator,
+			stakers.tiedValidator,
+			stakers.tiedDelegator,
+			stakers.firstValidator,
+		),
+		stakerIterator,
+	)
+
+	v.DeleteValidator(stakers.tiedValidator)
+
+	stakerIterator = v.GetStakerReverseIterator()
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(
+			stakers.lastValidator,
+			stakers.tiedDelegator,
+			stakers.firstValidator,
+		),
+		stakerIterator,
+	)
+}
+
 func TestBaseStakersDelegator(t *testing.T) {
 	staker := newTestStaker()
 	delegator := newTestStaker()
@@ -116,123 +283,3178 @@ func TestBaseStakersDelegator(t *testing.T) {
 
 	delegatorIterator := v.GetDelegatorIterator(delegator.SubnetID, delegator.NodeID)
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+	require.Zero(t, v.NumDelegators(delegator.SubnetID, delegator.NodeID))
 
 	v.PutDelegator(delegator)
 
 	delegatorIterator = v.GetDelegatorIterator(delegator.SubnetID, ids.GenerateTestNodeID())
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+	require.Zero(t, v.NumDelegators(delegator.SubnetID, ids.GenerateTestNodeID()))
 
 	delegatorIterator = v.GetDelegatorIterator(delegator.SubnetID, delegator.NodeID)
 	assertIteratorsEqual(t, iterator.FromSlice(delegator), delegatorIterator)
+	require.Equal(t, 1, v.NumDelegators(delegator.SubnetID, delegator.NodeID))
 
 	v.DeleteDelegator(delegator)
 
 	delegatorIterator = v.GetDelegatorIterator(delegator.SubnetID, delegator.NodeID)
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+	require.Zero(t, v.NumDelegators(delegator.SubnetID, delegator.NodeID))
 
 	v.PutValidator(staker)
 
 	v.PutDelegator(delegator)
+	require.Equal(t, 1, v.NumDelegators(delegator.SubnetID, delegator.NodeID))
 	v.DeleteDelegator(delegator)
+	require.Zero(t, v.NumDelegators(delegator.SubnetID, delegator.NodeID))
 
 	delegatorIterator = v.GetDelegatorIterator(staker.SubnetID, staker.NodeID)
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+	require.Zero(t, v.NumDelegators(staker.SubnetID, staker.NodeID))
 }
 
-func TestDiffStakersValidator(t *testing.T) {
+func TestBaseStakersExportStakers(t *testing.T) {
 	require := require.New(t)
-	staker := newTestStaker()
-	delegator := newTestStaker()
 
-	v := diffStakers{}
+	var (
+		subnetID = ids.GenerateTestID()
+		from     = time.Unix(1000, 0)
+		to       = time.Unix(2000, 0)
+	)
+	newStaker := func(start, end int64, priority txs.Priority) *Staker {
+		staker := newTestValidator(subnetID, 1)
+		staker.StartTime = time.Unix(start, 0)
+		staker.EndTime = time.Unix(end, 0)
+		staker.NextTime = staker.EndTime
+		staker.Priority = priority
+		return staker
+	}
 
+	var (
+		overlapsStart  = newStaker(500, 1500, txs.SubnetPermissionlessValidatorCurrentPriority)
+		contained      = newStaker(1200, 1800, txs.SubnetPermissionlessValidatorCurrentPriority)
+		overlapsEnd    = newStaker(1900, 2500, txs.SubnetPermissionlessValidatorCurrentPriority)
+		spansWindow    = newStaker(0, 3000, txs.SubnetPermissionlessValidatorCurrentPriority)
+		endsAtFrom     = newStaker(0, 1000, txs.SubnetPermissionlessValidatorCurrentPriority)
+		startsAtTo     = newStaker(2000, 3000, txs.SubnetPermissionlessValidatorCurrentPriority)
+		wrongPriority  = newStaker(1200, 1800, txs.SubnetPermissionedValidatorCurrentPriority)
+		delegator      = newStaker(1100, 1700, txs.SubnetPermissionlessDelegatorCurrentPriority)
+		otherSubnet    = newStaker(1200, 1800, txs.SubnetPermissionlessValidatorCurrentPriority)
+		validatorsOnly = set.Of(txs.SubnetPermissionlessValidatorCurrentPriority)
+	)
+	otherSubnet.SubnetID = ids.GenerateTestID()
+
+	v := newBaseStakers()
+	for _, staker := range []*Staker{overlapsStart, contained, overlapsEnd, spansWindow, endsAtFrom, startsAtTo, wrongPriority, otherSubnet} {
+		v.PutValidator(staker)
+	}
+	delegator.NodeID = contained.NodeID
 	v.PutDelegator(delegator)
 
-	// validators not available in the diff are marked as unmodified
-	_, status := v.GetValidator(ids.GenerateTestID(), delegator.NodeID)
-	require.Equal(unmodified, status)
+	require.Equal(
+		[]*Staker{overlapsStart, contained, overlapsEnd, spansWindow},
+		v.ExportStakers(subnetID, from, to, validatorsOnly),
+	)
+	require.Equal(
+		[]*Staker{overlapsStart, delegator, contained, overlapsEnd, spansWindow},
+		v.ExportStakers(
+			subnetID,
+			from,
+			to,
+			set.Of(
+				txs.SubnetPermissionlessValidatorCurrentPriority,
+				txs.SubnetPermissionlessDelegatorCurrentPriority,
+			),
+		),
+	)
+	require.Empty(v.ExportStakers(subnetID, from, to, nil))
+}
 
-	_, status = v.GetValidator(delegator.SubnetID, ids.GenerateTestNodeID())
-	require.Equal(unmodified, status)
+func TestBaseStakersFindCrossSubnetDuplicateTxIDs(t *testing.T) {
+	require := require.New(t)
 
-	// delegator addition shouldn't change validatorStatus
-	_, status = v.GetValidator(delegator.SubnetID, delegator.NodeID)
-	require.Equal(unmodified, status)
+	var (
+		validator     = newTestStaker()
+		delegator     = newTestStaker()
+		unique        = newTestStaker()
+		sameSubnet    = newTestStaker()
+		sameSubnetDup = newTestStaker()
+	)
 
-	stakerIterator := v.GetStakerIterator(iterator.Empty[*Staker]{})
-	assertIteratorsEqual(t, iterator.FromSlice(delegator), stakerIterator)
+	v := newBaseStakers()
+	v.PutValidator(validator)
+	v.PutValidator(unique)
+	require.Empty(v.FindCrossSubnetDuplicateTxIDs())
 
-	require.NoError(v.PutValidator(staker))
+	// Reuse the validator's TxID for a delegator on another subnet.
+	delegator.TxID = validator.TxID
+	delegator.NextTime = validator.NextTime.Add(time.Second)
+	v.PutDelegator(delegator)
 
-	returnedStaker, status := v.GetValidator(staker.SubnetID, staker.NodeID)
-	require.Equal(added, status)
-	require.Equal(staker, returnedStaker)
+	// Stakers sharing a TxID on the same subnet aren't cross-subnet
+	// duplicates.
+	sameSubnet.SubnetID = unique.SubnetID
+	sameSubnetDup.SubnetID = unique.SubnetID
+	sameSubnetDup.TxID = sameSubnet.TxID
+	v.PutDelegator(sameSubnet)
+	v.PutDelegator(sameSubnetDup)
+
+	expected := []*Staker{validator, delegator}
+	slices.SortFunc(expected, func(a, b *Staker) int {
+		return a.SubnetID.Compare(b.SubnetID)
+	})
+	require.Equal(
+		map[ids.ID][]*Staker{
+			validator.TxID: expected,
+		},
+		v.FindCrossSubnetDuplicateTxIDs(),
+	)
+}
 
-	v.DeleteValidator(staker)
+func TestBaseStakersPutBatch(t *testing.T) {
+	var (
+		subnetIDs  = []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+		validators []*Staker
+		delegators []*Staker
+	)
+	for i := 0; i < 10; i++ {
+		validator := newTestStaker()
+		validator.SubnetID = subnetIDs[i%len(subnetIDs)]
+		validators = append(validators, validator)
+
+		// Delegate to every other validator multiple times.
+		for j := 0; j < i%2*3; j++ {
+			delegator := newTestStaker()
+			delegator.SubnetID = validator.SubnetID
+			delegator.NodeID = validator.NodeID
+			delegators = append(delegators, delegator)
+		}
+	}
 
-	// Validators created and deleted in the same diff are marked as unmodified.
-	// This means they won't be pushed to baseState if diff.Apply(baseState) is
-	// called.
-	_, status = v.GetValidator(staker.SubnetID, staker.NodeID)
-	require.Equal(unmodified, status)
+	// Delegators without a validator must also be supported.
+	orphan := newTestStaker()
+	orphan.SubnetID = subnetIDs[0]
+	delegators = append(delegators, orphan)
 
-	stakerIterator = v.GetStakerIterator(iterator.Empty[*Staker]{})
-	assertIteratorsEqual(t, iterator.FromSlice(delegator), stakerIterator)
+	// Replacing a staker must behave as it does with the singular methods.
+	replacement := *validators[0]
+	validators = append(validators, &replacement)
+
+	expected := newBaseStakers()
+	for _, validator := range validators {
+		expected.PutValidator(validator)
+	}
+	for _, delegator := range delegators {
+		expected.PutDelegator(delegator)
+	}
+
+	actual := newBaseStakers()
+	actual.PutValidators(validators)
+	actual.PutDelegators(delegators)
+
+	requireBaseStakersEqual(t, expected, actual)
 }
 
-func TestDiffStakersDeleteValidator(t *testing.T) {
+func TestBaseStakersPutBatchPruning(t *testing.T) {
 	require := require.New(t)
 	staker := newTestStaker()
 	delegator := newTestStaker()
+	delegator.SubnetID = staker.SubnetID
+	delegator.NodeID = staker.NodeID
 
-	v := diffStakers{}
-
-	_, status := v.GetValidator(ids.GenerateTestID(), delegator.NodeID)
-	require.Equal(unmodified, status)
+	v := newBaseStakers()
+	v.PutValidators([]*Staker{staker})
+	v.PutDelegators([]*Staker{delegator})
 
 	v.DeleteValidator(staker)
 
-	returnedStaker, status := v.GetValidator(staker.SubnetID, staker.NodeID)
-	require.Equal(deleted, status)
-	require.Nil(returnedStaker)
+	_, err := v.GetValidator(staker.SubnetID, staker.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	v.DeleteDelegator(delegator)
+
+	require.Empty(v.validators)
+
+	// Empty batches must not allocate any subnets.
+	v.PutValidators(nil)
+	v.PutDelegators(nil)
+
+	require.Empty(v.validators)
 }
 
-func TestDiffStakersDelegator(t *testing.T) {
+func TestBaseStakersDeduplicateDelegators(t *testing.T) {
+	require := require.New(t)
 	staker := newTestStaker()
 	delegator := newTestStaker()
+	delegator.SubnetID = staker.SubnetID
+	delegator.NodeID = staker.NodeID
 
-	v := diffStakers{}
+	v := newBaseStakers()
 
-	require.NoError(t, v.PutValidator(staker))
+	require.Zero(v.DeduplicateDelegators(staker.SubnetID, staker.NodeID))
 
-	delegatorIterator := v.GetDelegatorIterator(iterator.Empty[*Staker]{}, ids.GenerateTestID(), delegator.NodeID)
+	v.PutValidator(staker)
+	v.PutDelegator(delegator)
+
+	require.Zero(v.DeduplicateDelegators(staker.SubnetID, staker.NodeID))
+
+	// Inject a second entry for the same delegator tx.
+	duplicate := *delegator
+	duplicate.NextTime = delegator.NextTime.Add(time.Second)
+	v.PutDelegator(&duplicate)
+
+	require.Equal(1, v.DeduplicateDelegators(staker.SubnetID, staker.NodeID))
+
+	delegatorIterator := v.GetDelegatorIterator(staker.SubnetID, staker.NodeID)
+	assertIteratorsEqual(t, iterator.FromSlice(delegator), delegatorIterator)
+	require.Equal(2, v.stakers.Len())
+	require.Equal(1, v.NumDelegators(staker.SubnetID, staker.NodeID))
+	require.NoError(v.VerifyTotals())
+
+	// Only the kept delegator must be written, and its entry must not be
+	// deleted.
+	validatorDiff := v.validatorDiffs[staker.SubnetID][staker.NodeID]
+	require.Empty(validatorDiff.deletedDelegators)
+	assertIteratorsEqual(t, iterator.FromSlice(delegator), iterator.FromTree(validatorDiff.addedDelegators))
+}
+
+func TestBaseStakersDeleteValidatorCascade(t *testing.T) {
+	require := require.New(t)
+	validator := newTestStaker()
+
+	v := newBaseStakers()
+
+	_, err := v.DeleteValidatorCascade(validator.SubnetID, validator.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	v.PutValidator(validator)
+
+	const numDelegators = 3
+	for i := 0; i < numDelegators; i++ {
+		delegator := newTestStaker()
+		delegator.SubnetID = validator.SubnetID
+		delegator.NodeID = validator.NodeID
+		v.PutDelegator(delegator)
+	}
+
+	// Stakers of other validators must not be removed.
+	other := newTestStaker()
+	v.PutValidator(other)
+
+	numDeleted, err := v.DeleteValidatorCascade(validator.SubnetID, validator.NodeID)
+	require.NoError(err)
+	require.Equal(numDelegators, numDeleted)
+
+	_, err = v.GetValidator(validator.SubnetID, validator.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	delegatorIterator := v.GetDelegatorIterator(validator.SubnetID, validator.NodeID)
 	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
 
+	stakerIterator := v.GetStakerIterator()
+	assertIteratorsEqual(t, iterator.FromSlice(other), stakerIterator)
+
+	validatorDiff := v.validatorDiffs[validator.SubnetID][validator.NodeID]
+	require.Equal(deleted, validatorDiff.validatorStatus)
+	require.Len(validatorDiff.deletedDelegators, numDelegators)
+	require.NoError(v.VerifyTotals())
+}
+
+func TestBaseStakersGetAllStakersIterator(t *testing.T) {
+	v := newBaseStakers()
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.GetAllStakersIterator())
+
+	var (
+		subnetA  = ids.ID{1}
+		subnetB  = ids.ID{2}
+		nextTime = time.Unix(1000, 0)
+	)
+	newStaker := func(subnetID ids.ID, nextTime time.Time, txID ids.ID) *Staker {
+		staker := newTestStaker()
+		staker.SubnetID = subnetID
+		staker.NextTime = nextTime
+		staker.TxID = txID
+		return staker
+	}
+
+	var (
+		a0 = newStaker(subnetA, nextTime, ids.ID{1})
+		a1 = newStaker(subnetA, nextTime, ids.ID{2})
+		a2 = newStaker(subnetA, nextTime.Add(time.Second), ids.ID{0})
+		b0 = newStaker(subnetB, nextTime.Add(-time.Second), ids.ID{3})
+		b1 = newStaker(subnetB, nextTime, ids.ID{4})
+	)
+	a1.NodeID = a0.NodeID
+	b1.NodeID = b0.NodeID
+
+	// Insert the stakers out of order.
+	v.PutValidator(b0)
+	v.PutDelegator(a1)
+	v.PutValidator(a2)
+	v.PutValidator(a0)
+	v.PutDelegator(b1)
+
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(a0, a1, a2, b0, b1),
+		v.GetAllStakersIterator(),
+	)
+}
+
+func TestBaseStakersGetValidatorIteratorByTxID(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.GetValidatorIteratorByTxID(subnetID))
+
+	validators := make([]*Staker, 3)
+	for i := range validators {
+		validators[i] = newTestValidator(subnetID, 1)
+		validators[i].TxID = ids.ID{byte(i + 1)}
+	}
+
+	// Insert the validators out of TxID order.
+	for _, i := range []int{2, 0, 1} {
+		v.PutValidator(validators[i])
+	}
+
+	// Delegators and validators of other subnets must not be returned.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validators[0].NodeID
+	delegator.TxID = ids.Empty
 	v.PutDelegator(delegator)
+	v.PutValidator(newTestStaker())
 
-	delegatorIterator = v.GetDelegatorIterator(iterator.Empty[*Staker]{}, delegator.SubnetID, delegator.NodeID)
-	assertIteratorsEqual(t, iterator.FromSlice(delegator), delegatorIterator)
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(validators...),
+		v.GetValidatorIteratorByTxID(subnetID),
+	)
+}
+
+func TestBaseStakersAverageValidatorWeight(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, err := v.AverageValidatorWeight(subnetID)
+	require.ErrorIs(err, errNoValidators)
+
+	for _, weight := range []uint64{1, 2, 6} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	// Delegators must not impact the average.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.Weight = 100
+	v.PutDelegator(delegator)
+
+	average, err := v.AverageValidatorWeight(subnetID)
+	require.NoError(err)
+	require.InDelta(3.0, average, floatDelta)
+}
+
+func TestBaseStakersDelegatorValidatorRatio(t *testing.T) {
+	tests := []struct {
+		name          string
+		numValidators int
+		numDelegators int
+		expected      float64
+		expectedErr   error
+	}{
+		{
+			name:        "no stakers",
+			expectedErr: errNoValidators,
+		},
+		{
+			name:          "only delegators",
+			numDelegators: 2,
+			expectedErr:   errNoValidators,
+		},
+		{
+			name:          "only validators",
+			numValidators: 2,
+			expected:      0,
+		},
+		{
+			name:          "more delegators",
+			numValidators: 2,
+			numDelegators: 5,
+			expected:      2.5,
+		},
+		{
+			name:          "more validators",
+			numValidators: 4,
+			numDelegators: 1,
+			expected:      0.25,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			subnetID := ids.GenerateTestID()
+
+			v := newBaseStakers()
+			for i := 0; i < test.numValidators; i++ {
+				v.PutValidator(newTestValidator(subnetID, 1))
+			}
+			for i := 0; i < test.numDelegators; i++ {
+				delegator := newTestStaker()
+				delegator.SubnetID = subnetID
+				v.PutDelegator(delegator)
+			}
+
+			// Stakers of other subnets must not impact the ratio.
+			v.PutValidator(newTestStaker())
+
+			ratio, err := v.DelegatorValidatorRatio(subnetID)
+			require.ErrorIs(err, test.expectedErr)
+			require.InDelta(test.expected, ratio, floatDelta)
+		})
+	}
+}
+
+func TestBaseStakersDelegatorValidatorRatioMaintained(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		validator = newTestStaker()
+		delegator = newTestStaker()
+	)
+	delegator.SubnetID = validator.SubnetID
+	delegator.NodeID = validator.NodeID
+
+	v := newBaseStakers()
+	v.PutValidator(validator)
+	v.PutDelegator(delegator)
+
+	ratio, err := v.DelegatorValidatorRatio(validator.SubnetID)
+	require.NoError(err)
+	require.InDelta(1.0, ratio, floatDelta)
+
+	// Replacing stakers must not change the counts.
+	v.PutValidator(validator)
+	v.PutDelegator(delegator)
+
+	ratio, err = v.DelegatorValidatorRatio(validator.SubnetID)
+	require.NoError(err)
+	require.InDelta(1.0, ratio, floatDelta)
 
 	v.DeleteDelegator(delegator)
 
-	delegatorIterator = v.GetDelegatorIterator(iterator.Empty[*Staker]{}, ids.GenerateTestID(), delegator.NodeID)
-	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+	ratio, err = v.DelegatorValidatorRatio(validator.SubnetID)
+	require.NoError(err)
+	require.Zero(ratio)
+
+	v.DeleteValidator(validator)
+
+	_, err = v.DelegatorValidatorRatio(validator.SubnetID)
+	require.ErrorIs(err, errNoValidators)
+	require.Empty(v.subnetCounts)
 }
 
-func newTestStaker() *Staker {
-	startTime := time.Now().Round(time.Second)
-	endTime := startTime.Add(genesistest.DefaultValidatorDuration)
-	return &Staker{
-		TxID:            ids.GenerateTestID(),
-		NodeID:          ids.GenerateTestNodeID(),
-		SubnetID:        ids.GenerateTestID(),
-		Weight:          1,
-		StartTime:       startTime,
-		EndTime:         endTime,
-		PotentialReward: 1,
+func TestBaseStakersValidatorWeightVariance(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
 
-		NextTime: endTime,
-		Priority: txs.PrimaryNetworkDelegatorCurrentPriority,
+	v := newBaseStakers()
+
+	_, err := v.ValidatorWeightVariance(subnetID)
+	require.ErrorIs(err, errNoValidators)
+
+	v.PutValidator(newTestValidator(subnetID, 5))
+
+	variance, err := v.ValidatorWeightVariance(subnetID)
+	require.NoError(err)
+	require.Zero(variance)
+
+	for _, weight := range []uint64{2, 4, 4, 4, 5, 7, 9} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	// Delegators must not impact the variance.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.Weight = 100
+	v.PutDelegator(delegator)
+
+	// Weights {5, 2, 4, 4, 4, 5, 7, 9} have a mean of 5.
+	variance, err = v.ValidatorWeightVariance(subnetID)
+	require.NoError(err)
+	require.InDelta(4.0, variance, floatDelta)
+}
+
+func TestBaseStakersStakeCoefficientOfVariation(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     []uint64
+		expected    float64
+		expectedErr error
+	}{
+		{
+			name:        "no validators",
+			expectedErr: errNoValidators,
+		},
+		{
+			name:        "zero mean",
+			weights:     []uint64{0, 0},
+			expectedErr: errZeroWeight,
+		},
+		{
+			name:     "uniform",
+			weights:  []uint64{3, 3, 3},
+			expected: 0,
+		},
+		{
+			// mean 5, standard deviation 2
+			name:     "dispersed",
+			weights:  []uint64{2, 4, 4, 4, 5, 5, 7, 9},
+			expected: 0.4,
+		},
+		{
+			// mean 1, standard deviation 1
+			name:     "one staked validator",
+			weights:  []uint64{0, 2},
+			expected: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			subnetID := ids.GenerateTestID()
+
+			v := newBaseStakers()
+			for _, weight := range test.weights {
+				v.PutValidator(newTestValidator(subnetID, weight))
+			}
+
+			cv, err := v.StakeCoefficientOfVariation(subnetID)
+			require.ErrorIs(err, test.expectedErr)
+			require.InDelta(test.expected, cv, floatDelta)
+		})
+	}
+}
+
+func TestBaseStakersRewardShares(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	require.Empty(v.RewardShares(subnetID))
+
+	zeroReward := newTestValidator(subnetID, 1)
+	zeroReward.PotentialReward = 0
+	v.PutValidator(zeroReward)
+	require.Empty(v.RewardShares(subnetID))
+
+	var validators []*Staker
+	for _, reward := range []uint64{10, 30, 60} {
+		validator := newTestValidator(subnetID, 1)
+		validator.PotentialReward = reward
+		v.PutValidator(validator)
+		validators = append(validators, validator)
+	}
+
+	// Delegators must not impact the shares.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validators[0].NodeID
+	delegator.PotentialReward = 100
+	v.PutDelegator(delegator)
+
+	shares := v.RewardShares(subnetID)
+	require.Len(shares, 4)
+	require.Zero(shares[zeroReward.NodeID])
+	require.InDelta(0.1, shares[validators[0].NodeID], floatDelta)
+	require.InDelta(0.3, shares[validators[1].NodeID], floatDelta)
+	require.InDelta(0.6, shares[validators[2].NodeID], floatDelta)
+
+	var total float64
+	for _, share := range shares {
+		total += share
+	}
+	require.InDelta(1.0, total, floatDelta)
+}
+
+func TestBaseStakersWeightChangesSince(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID  = ids.GenerateTestID()
+		increased = newTestValidator(subnetID, 15)
+		decreased = newTestValidator(subnetID, 5)
+		unchanged = newTestValidator(subnetID, 10)
+		added     = newTestValidator(subnetID, 7)
+		removedID = ids.GenerateTestNodeID()
+	)
+
+	v := newBaseStakers()
+	for _, validator := range []*Staker{increased, decreased, unchanged, added} {
+		v.PutValidator(validator)
+	}
+
+	snapshot := map[ids.NodeID]uint64{
+		increased.NodeID: 10,
+		decreased.NodeID: 10,
+		unchanged.NodeID: 10,
+		removedID:        3,
+	}
+	require.Equal(
+		map[ids.NodeID]int64{
+			increased.NodeID: 5,
+			decreased.NodeID: -5,
+			added.NodeID:     7,
+			removedID:        -3,
+		},
+		v.WeightChangesSince(subnetID, snapshot),
+	)
+
+	// Comparing against the current weights must report no changes.
+	current := make(map[ids.NodeID]uint64)
+	for _, validator := range v.subnetValidators(subnetID) {
+		current[validator.NodeID] = validator.Weight
+	}
+	require.Empty(v.WeightChangesSince(subnetID, current))
+}
+
+func TestBaseStakersTotalWeightAcrossSubnets(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetA = ids.GenerateTestID()
+		subnetB = ids.GenerateTestID()
+		subnetC = ids.GenerateTestID()
+	)
+
+	v := newBaseStakers()
+
+	total, err := v.TotalWeightAcrossSubnets([]ids.ID{subnetA, subnetB})
+	require.NoError(err)
+	require.Zero(total)
+
+	v.PutValidator(newTestValidator(subnetA, 1))
+	v.PutValidator(newTestValidator(subnetA, 2))
+	v.PutValidator(newTestValidator(subnetB, 4))
+	v.PutValidator(newTestValidator(subnetC, 8))
+
+	// Delegators must not be counted.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetA
+	delegator.Weight = 16
+	v.PutDelegator(delegator)
+
+	total, err = v.TotalWeightAcrossSubnets([]ids.ID{subnetA, subnetB})
+	require.NoError(err)
+	require.Equal(uint64(7), total)
+
+	total, err = v.TotalWeightAcrossSubnets([]ids.ID{subnetA, subnetA})
+	require.NoError(err)
+	require.Equal(uint64(3), total)
+
+	// Subnet C now holds the maximum weight, so combining it with any other
+	// subnet must overflow.
+	v.PutValidator(newTestValidator(subnetC, math.MaxUint64-8))
+
+	_, err = v.TotalWeightAcrossSubnets([]ids.ID{subnetB, subnetC})
+	require.ErrorIs(err, safemath.ErrOverflow)
+}
+
+func TestBaseStakersSubnetsByTotalStake(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetA = ids.ID{1}
+		subnetB = ids.ID{2}
+		subnetC = ids.ID{3}
+		subnetD = ids.ID{4}
+	)
+
+	v := newBaseStakers()
+	require.Empty(v.SubnetsByTotalStake())
+
+	v.PutValidator(newTestValidator(subnetC, 1))
+	v.PutValidator(newTestValidator(subnetC, 2))
+	v.PutValidator(newTestValidator(subnetB, 10))
+	v.PutValidator(newTestValidator(subnetA, 3))
+
+	// Delegators must not be counted.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetA
+	delegator.Weight = 100
+	v.PutDelegator(delegator)
+
+	// Subnets without validators must not be returned.
+	delegator = newTestStaker()
+	delegator.SubnetID = subnetD
+	v.PutDelegator(delegator)
+
+	require.Equal(
+		[]SubnetStake{
+			{SubnetID: subnetB, Weight: 10},
+			{SubnetID: subnetA, Weight: 3}, // ties are ordered by SubnetID
+			{SubnetID: subnetC, Weight: 3},
+		},
+		v.SubnetsByTotalStake(),
+	)
+}
+
+func TestBaseStakersTotalPendingReward(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID      = ids.GenerateTestID()
+		otherSubnetID = ids.GenerateTestID()
+	)
+
+	newStaker := func(subnetID ids.ID, nodeID ids.NodeID, potentialReward uint64) *Staker {
+		staker := newTestStaker()
+		staker.SubnetID = subnetID
+		staker.NodeID = nodeID
+		staker.PotentialReward = potentialReward
+		return staker
+	}
+
+	v := newBaseStakers()
+	total, err := v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Zero(total)
+
+	var (
+		validatorA = newStaker(subnetID, ids.GenerateTestNodeID(), 100)
+		validatorB = newStaker(subnetID, ids.GenerateTestNodeID(), 200)
+		delegatorA = newStaker(subnetID, validatorA.NodeID, 10)
+		delegatorB = newStaker(subnetID, validatorB.NodeID, 20)
+	)
+	v.PutValidator(validatorA)
+	v.PutValidator(validatorB)
+	v.PutDelegator(delegatorA)
+	v.PutDelegator(delegatorB)
+
+	// Stakers on other subnets must not be counted.
+	v.PutValidator(newStaker(otherSubnetID, ids.GenerateTestNodeID(), 1000))
+
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(330), total)
+
+	v.DeleteDelegator(delegatorB)
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(310), total)
+
+	// Slashing is not persisted, so it must not modify the potential reward.
+	require.NoError(v.SlashValidator(subnetID, validatorA.NodeID))
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(310), total)
+
+	// Replacing a validator must only count the replacement.
+	replacement := *validatorB
+	replacement.PotentialReward = 500
+	v.PutValidator(&replacement)
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(610), total)
+
+	// The total must be reported as an overflow while it exceeds a uint64, but
+	// must recover once the offending staker is removed.
+	large := newStaker(subnetID, ids.GenerateTestNodeID(), math.MaxUint64)
+	v.PutValidator(large)
+	_, err = v.TotalPendingReward(subnetID)
+	require.ErrorIs(err, safemath.ErrOverflow)
+
+	v.DeleteValidator(large)
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(610), total)
+
+	// Clones must maintain their totals independently.
+	clone := v.Clone()
+	clone.DeleteDelegator(delegatorA)
+	total, err = clone.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(600), total)
+
+	total, err = v.TotalPendingReward(subnetID)
+	require.NoError(err)
+	require.Equal(uint64(610), total)
+
+	total, err = v.TotalPendingReward(otherSubnetID)
+	require.NoError(err)
+	require.Equal(uint64(1000), total)
+}
+
+func TestBaseStakersIdleSubnets(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetA = ids.ID{1}
+		subnetB = ids.ID{2}
+		subnetC = ids.ID{3}
+		subnetD = ids.ID{4}
+
+		since = time.Unix(1000, 0)
+	)
+
+	v := newBaseStakers()
+	require.Empty(v.IdleSubnets(since))
+
+	// subnetA only has stakers added before [since].
+	v.SetTimestamp(time.Unix(100, 0))
+	v.PutValidator(newTestValidator(subnetA, 1))
+	v.PutValidator(newTestValidator(subnetB, 1))
+	validator := newTestValidator(subnetC, 1)
+	v.PutValidator(validator)
+	delegator := newTestValidator(subnetC, 1)
+	delegator.NodeID = validator.NodeID
+	delegator.EndTime = time.Unix(200, 0)
+	delegator.Priority = txs.SubnetPermissionlessDelegatorCurrentPriority
+	v.PutDelegator(delegator)
+	removed := newTestValidator(subnetD, 1)
+	v.PutValidator(removed)
+
+	v.SetTimestamp(time.Unix(200, 0))
+	v.PutValidator(newTestValidator(subnetA, 1))
+
+	// subnetB had a validator added at [since].
+	v.SetTimestamp(since)
+	v.PutValidator(newTestValidator(subnetB, 1))
+
+	// subnetC had a delegator removed after [since]. The removal must be
+	// recorded at the time it occurred rather than at the delegator's EndTime.
+	v.SetTimestamp(time.Unix(1500, 0))
+	v.DeleteDelegator(delegator)
+
+	// subnetD has no remaining stakers, so it must not be returned.
+	v.DeleteValidator(removed)
+
+	require.Equal([]ids.ID{subnetA}, v.IdleSubnets(since))
+
+	// Activity must be tracked independently by each clone.
+	clone := v.Clone()
+	clone.PutValidator(newTestValidator(subnetA, 1))
+	require.Empty(clone.IdleSubnets(since))
+	require.Equal([]ids.ID{subnetA}, v.IdleSubnets(since))
+
+	// Every subnet with stakers is idle since a later time.
+	require.Equal(
+		[]ids.ID{subnetA, subnetB, subnetC},
+		v.IdleSubnets(time.Unix(3000, 0)),
+	)
+}
+
+func TestBaseStakersMostDelegatedValidator(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, _, err := v.MostDelegatedValidator(subnetID)
+	require.ErrorIs(err, errNoValidators)
+
+	validators := []*Staker{
+		newTestValidator(subnetID, 10),
+		newTestValidator(subnetID, 10),
+		newTestValidator(subnetID, 10),
+	}
+	for _, validator := range validators {
+		v.PutValidator(validator)
+	}
+
+	for i, weights := range [][]uint64{
+		{1, 2, 3},
+		{7},
+		{},
+	} {
+		for _, weight := range weights {
+			delegator := newTestStaker()
+			delegator.SubnetID = subnetID
+			delegator.NodeID = validators[i].NodeID
+			delegator.Weight = weight
+			v.PutDelegator(delegator)
+		}
+	}
+
+	validator, weight, err := v.MostDelegatedValidator(subnetID)
+	require.NoError(err)
+	require.Equal(validators[1], validator)
+	require.Equal(uint64(7), weight)
+}
+
+func TestBaseStakersPartitionDelegators(t *testing.T) {
+	require := require.New(t)
+	validator := newTestStaker()
+
+	v := newBaseStakers()
+	v.PutValidator(validator)
+
+	require.Nil(v.PartitionDelegators(validator.SubnetID, validator.NodeID, 0))
+
+	partitions := v.PartitionDelegators(validator.SubnetID, validator.NodeID, 2)
+	require.Len(partitions, 2)
+	for _, partition := range partitions {
+		require.Empty(partition)
+	}
+
+	const numDelegators = 10
+	for i := 0; i < numDelegators; i++ {
+		delegator := newTestStaker()
+		delegator.SubnetID = validator.SubnetID
+		delegator.NodeID = validator.NodeID
+		delegator.NextTime = delegator.NextTime.Add(time.Duration(i) * time.Second)
+		v.PutDelegator(delegator)
+	}
+
+	partitions = v.PartitionDelegators(validator.SubnetID, validator.NodeID, 3)
+	require.Len(partitions, 3)
+	require.Len(partitions[0], 4)
+	require.Len(partitions[1], 3)
+	require.Len(partitions[2], 3)
+
+	// Every delegator must be assigned exactly once and in order.
+	var (
+		delegatorIterator = v.GetDelegatorIterator(validator.SubnetID, validator.NodeID)
+		partitioned       []*Staker
+	)
+	for _, partition := range partitions {
+		partitioned = append(partitioned, partition...)
+	}
+	assertIteratorsEqual(t, delegatorIterator, iterator.FromSlice(partitioned...))
+}
+
+func TestBaseStakersTimeWeightedAverageStake(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	from := time.Unix(100, 0)
+	to := time.Unix(200, 0)
+
+	v := newBaseStakers()
+
+	_, err := v.TimeWeightedAverageStake(subnetID, to, from)
+	require.ErrorIs(err, errInvalidTimeWindow)
+
+	average, err := v.TimeWeightedAverageStake(subnetID, from, to)
+	require.NoError(err)
+	require.Zero(average)
+
+	// Active for the entire window.
+	validator := newTestValidator(subnetID, 10)
+	validator.StartTime = time.Unix(0, 0)
+	validator.EndTime = time.Unix(300, 0)
+	v.PutValidator(validator)
+
+	// Active for the second half of the window.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validator.NodeID
+	delegator.Weight = 20
+	delegator.StartTime = time.Unix(150, 0)
+	delegator.EndTime = time.Unix(250, 0)
+	v.PutDelegator(delegator)
+
+	// Not active during the window.
+	expired := newTestValidator(subnetID, 1000)
+	expired.StartTime = time.Unix(0, 0)
+	expired.EndTime = time.Unix(100, 0)
+	v.PutValidator(expired)
+
+	average, err = v.TimeWeightedAverageStake(subnetID, from, to)
+	require.NoError(err)
+	require.InDelta(20.0, average, floatDelta)
+}
+
+func TestBaseStakersExpirationBuckets(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	require.Empty(v.ExpirationBuckets(subnetID, time.Minute))
+
+	for _, endTime := range []time.Time{
+		// Clustered expirations
+		time.Unix(60, 0),
+		time.Unix(61, 0),
+		time.Unix(119, 0),
+		// Spread expirations
+		time.Unix(180, 0),
+		time.Unix(600, 0),
+	} {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		v.PutValidator(validator)
+	}
+
+	// Stakers on other subnets must not be counted.
+	v.PutValidator(newTestStaker())
+
+	require.Equal(
+		map[time.Time]int{
+			time.Unix(60, 0):  3,
+			time.Unix(180, 0): 1,
+			time.Unix(600, 0): 1,
+		},
+		v.ExpirationBuckets(subnetID, time.Minute),
+	)
+}
+
+func TestBaseStakersExpiriesByDay(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	require.Empty(v.ExpiriesByDay(subnetID, time.UTC))
+
+	for _, endTime := range []time.Time{
+		time.Date(2024, time.January, 1, 3, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 1, 23, 59, 59, 0, time.UTC),
+		time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.January, 2, 12, 0, 0, 0, time.UTC),
+	} {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		v.PutValidator(validator)
+	}
+
+	// Stakers on other subnets must not be counted.
+	v.PutValidator(newTestStaker())
+
+	require.Equal(
+		map[string]int{
+			"2024-01-01": 2,
+			"2024-01-02": 2,
+		},
+		v.ExpiriesByDay(subnetID, time.UTC),
+	)
+
+	// Shifting the location moves expirations across day boundaries.
+	require.Equal(
+		map[string]int{
+			"2023-12-31": 1,
+			"2024-01-01": 2,
+			"2024-01-02": 1,
+		},
+		v.ExpiriesByDay(subnetID, time.FixedZone("UTC-5", -5*60*60)),
+	)
+	require.Equal(
+		map[string]int{
+			"2024-01-01": 1,
+			"2024-01-02": 3,
+		},
+		v.ExpiriesByDay(subnetID, time.FixedZone("UTC+9", 9*60*60)),
+	)
+}
+
+func TestBaseStakersValidatorRotationRate(t *testing.T) {
+	require := require.New(t)
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(0, 0)
+		day      = 24 * time.Hour
+	)
+
+	v := newBaseStakers()
+
+	_, err := v.ValidatorRotationRate(subnetID, 0, now)
+	require.ErrorIs(err, errInvalidTimeWindow)
+
+	rate, err := v.ValidatorRotationRate(subnetID, day, now)
+	require.NoError(err)
+	require.Zero(rate)
+
+	for _, endTime := range []time.Time{
+		// Already expired
+		now,
+		// Cluster within the first day
+		now.Add(time.Hour),
+		now.Add(2 * time.Hour),
+		now.Add(day),
+		// Cluster within the following week
+		now.Add(3 * day),
+		now.Add(7 * day),
+		// Beyond the window
+		now.Add(30 * day),
+	} {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		v.PutValidator(validator)
+	}
+
+	rate, err = v.ValidatorRotationRate(subnetID, day, now)
+	require.NoError(err)
+	require.InDelta(3.0, rate, floatDelta)
+
+	rate, err = v.ValidatorRotationRate(subnetID, 7*day, now)
+	require.NoError(err)
+	require.InDelta(5.0/7, rate, floatDelta)
+
+	rate, err = v.ValidatorRotationRate(subnetID, 12*time.Hour, now)
+	require.NoError(err)
+	require.InDelta(4.0, rate, floatDelta)
+}
+
+func TestBaseStakersExpiryQueuePosition(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	stakers := make([]*Staker, 3)
+	for i := range stakers {
+		stakers[i] = newTestValidator(subnetID, 1)
+		stakers[i].NextTime = time.Unix(int64(i), 0)
+		v.PutValidator(stakers[i])
+	}
+
+	// Stakers on other subnets must not affect the position.
+	otherStaker := newTestStaker()
+	otherStaker.NextTime = time.Unix(0, 0)
+	v.PutValidator(otherStaker)
+
+	_, err := v.ExpiryQueuePosition(subnetID, ids.GenerateTestID())
+	require.ErrorIs(err, database.ErrNotFound)
+
+	_, err = v.ExpiryQueuePosition(subnetID, otherStaker.TxID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	for i, staker := range stakers {
+		position, err := v.ExpiryQueuePosition(subnetID, staker.TxID)
+		require.NoError(err)
+		require.Equal(i, position)
+	}
+
+	v.DeleteValidator(stakers[0])
+
+	_, err = v.ExpiryQueuePosition(subnetID, stakers[0].TxID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	for i, staker := range stakers[1:] {
+		position, err := v.ExpiryQueuePosition(subnetID, staker.TxID)
+		require.NoError(err)
+		require.Equal(i, position)
+	}
+}
+
+func TestBaseStakersStakeConcentration(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, err := v.StakeConcentration(subnetID, 1)
+	require.ErrorIs(err, errNoValidators)
+
+	for _, weight := range []uint64{10, 40, 20, 30} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	tests := []struct {
+		k        int
+		expected float64
+	}{
+		{k: 0, expected: 0},
+		{k: 1, expected: .4},
+		{k: 2, expected: .7},
+		{k: 4, expected: 1},
+		{k: 10, expected: 1},
+	}
+	for _, test := range tests {
+		concentration, err := v.StakeConcentration(subnetID, test.k)
+		require.NoError(err)
+		require.InDelta(test.expected, concentration, floatDelta)
+	}
+}
+
+func TestBaseStakersStakeHerfindahlIndex(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, err := v.StakeHerfindahlIndex(subnetID)
+	require.ErrorIs(err, errNoValidators)
+
+	v.PutValidator(newTestValidator(subnetID, 0))
+
+	_, err = v.StakeHerfindahlIndex(subnetID)
+	require.ErrorIs(err, errZeroWeight)
+
+	for _, weight := range []uint64{5, 3, 2} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	// Delegators must not impact the index.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.Weight = 100
+	v.PutDelegator(delegator)
+
+	// 0.5^2 + 0.3^2 + 0.2^2
+	index, err := v.StakeHerfindahlIndex(subnetID)
+	require.NoError(err)
+	require.InDelta(0.38, index, floatDelta)
+}
+
+func TestBaseStakersLongestTenuredValidator(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+
+	v := newBaseStakers()
+
+	_, _, err := v.LongestTenuredValidator(subnetID, now)
+	require.ErrorIs(err, errNoValidators)
+
+	var longest *Staker
+	for i, tenure := range []time.Duration{time.Minute, time.Hour, time.Second} {
+		validator := newTestValidator(subnetID, uint64(i+1))
+		validator.StartTime = now.Add(-tenure)
+		v.PutValidator(validator)
+
+		if tenure == time.Hour {
+			longest = validator
+		}
+	}
+
+	// Delegators and validators of other subnets must be ignored.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.StartTime = now.Add(-24 * time.Hour)
+	v.PutDelegator(delegator)
+
+	other := newTestValidator(ids.GenerateTestID(), 1)
+	other.StartTime = now.Add(-24 * time.Hour)
+	v.PutValidator(other)
+
+	validator, tenure, err := v.LongestTenuredValidator(subnetID, now)
+	require.NoError(err)
+	require.Equal(longest, validator)
+	require.Equal(time.Hour, tenure)
+}
+
+func TestBaseStakersUpcomingStakers(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+	newStaker := func(subnetID ids.ID, nextTime time.Time, priority txs.Priority) *Staker {
+		staker := newTestValidator(subnetID, 1)
+		staker.NextTime = nextTime
+		staker.Priority = priority
+		return staker
+	}
+
+	var (
+		atNow       = newStaker(subnetID, now, txs.SubnetPermissionedValidatorCurrentPriority)
+		validator   = newStaker(subnetID, now.Add(2*time.Minute), txs.SubnetPermissionedValidatorCurrentPriority)
+		delegator   = newStaker(subnetID, now.Add(time.Minute), txs.SubnetPermissionlessDelegatorCurrentPriority)
+		atEnd       = newStaker(subnetID, now.Add(time.Hour), txs.SubnetPermissionedValidatorCurrentPriority)
+		afterEnd    = newStaker(subnetID, now.Add(time.Hour+time.Second), txs.SubnetPermissionedValidatorCurrentPriority)
+		otherSubnet = newStaker(ids.GenerateTestID(), now.Add(time.Minute), txs.SubnetPermissionedValidatorCurrentPriority)
+	)
+
+	v := newBaseStakers()
+	require.Empty(v.UpcomingStakers(subnetID, now, time.Hour))
+
+	for _, staker := range []*Staker{atNow, validator, atEnd, afterEnd, otherSubnet} {
+		v.PutValidator(staker)
+	}
+	delegator.NodeID = validator.NodeID
+	v.PutDelegator(delegator)
+
+	require.Equal(
+		[]*Staker{delegator, validator, atEnd},
+		v.UpcomingStakers(subnetID, now, time.Hour),
+	)
+	require.Empty(v.UpcomingStakers(subnetID, now, 0))
+}
+
+func TestBaseStakersNakamotoCoefficient(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     []uint64
+		expected    int
+		expectedErr error
+	}{
+		{
+			name:        "no validators",
+			expectedErr: errNoValidators,
+		},
+		{
+			name:        "zero weight",
+			weights:     []uint64{0, 0},
+			expectedErr: errZeroWeight,
+		},
+		{
+			name:     "single validator",
+			weights:  []uint64{10},
+			expected: 1,
+		},
+		{
+			name:     "dominant validator",
+			weights:  []uint64{40, 30, 20, 10},
+			expected: 1,
+		},
+		{
+			name:     "exactly one third is not enough",
+			weights:  []uint64{1, 1, 1},
+			expected: 2,
+		},
+		{
+			name:     "uniform",
+			weights:  []uint64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			expected: 4,
+		},
+		{
+			name:     "unsorted",
+			weights:  []uint64{5, 20, 5, 30, 40},
+			expected: 1,
+		},
+		{
+			name:     "long tail",
+			weights:  []uint64{20, 20, 10, 10, 5, 5, 5, 5, 5, 5},
+			expected: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			subnetID := ids.GenerateTestID()
+
+			v := newBaseStakers()
+			for _, weight := range test.weights {
+				v.PutValidator(newTestValidator(subnetID, weight))
+			}
+
+			// Delegators must not impact the coefficient.
+			delegator := newTestStaker()
+			delegator.SubnetID = subnetID
+			delegator.Weight = 1000
+			v.PutDelegator(delegator)
+
+			coefficient, err := v.NakamotoCoefficient(subnetID)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expected, coefficient)
+		})
+	}
+}
+
+func TestBaseStakersMinValidatorsForThreshold(t *testing.T) {
+	tests := []struct {
+		name        string
+		weights     []uint64
+		threshold   uint64
+		expected    int
+		expectedErr error
+	}{
+		{
+			name:      "zero threshold",
+			weights:   []uint64{10},
+			threshold: 0,
+			expected:  0,
+		},
+		{
+			name:        "no validators",
+			threshold:   1,
+			expectedErr: errUnreachableStake,
+		},
+		{
+			name:      "single heaviest validator",
+			weights:   []uint64{5, 50, 20},
+			threshold: 50,
+			expected:  1,
+		},
+		{
+			name:      "just above heaviest validator",
+			weights:   []uint64{5, 50, 20},
+			threshold: 51,
+			expected:  2,
+		},
+		{
+			name:      "total stake",
+			weights:   []uint64{5, 50, 20},
+			threshold: 75,
+			expected:  3,
+		},
+		{
+			name:        "above total stake",
+			weights:     []uint64{5, 50, 20},
+			threshold:   76,
+			expectedErr: errUnreachableStake,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			subnetID := ids.GenerateTestID()
+
+			v := newBaseStakers()
+			for _, weight := range test.weights {
+				v.PutValidator(newTestValidator(subnetID, weight))
+			}
+
+			// Delegators must not count towards the threshold.
+			delegator := newTestStaker()
+			delegator.SubnetID = subnetID
+			delegator.Weight = 1000
+			v.PutDelegator(delegator)
+
+			numValidators, err := v.MinValidatorsForThreshold(subnetID, test.threshold)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expected, numValidators)
+		})
+	}
+}
+
+func TestBaseStakersSimulateAddValidator(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, _, err := v.SimulateAddValidator(subnetID, 0)
+	require.ErrorIs(err, errZeroWeight)
+
+	// Simulating on an empty subnet yields a single validator.
+	total, index, err := v.SimulateAddValidator(subnetID, 5)
+	require.NoError(err)
+	require.Equal(uint64(5), total)
+	require.InDelta(1.0, index, floatDelta)
+
+	for _, weight := range []uint64{3, 2} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	total, index, err = v.SimulateAddValidator(subnetID, 5)
+	require.NoError(err)
+
+	// The simulation must not modify the staker set.
+	require.Len(v.subnetValidators(subnetID), 2)
+
+	v.PutValidator(newTestValidator(subnetID, 5))
+
+	expectedTotal, err := v.TotalWeightAcrossSubnets([]ids.ID{subnetID})
+	require.NoError(err)
+	require.Equal(expectedTotal, total)
+
+	expectedIndex, err := v.StakeHerfindahlIndex(subnetID)
+	require.NoError(err)
+	require.InDelta(expectedIndex, index, floatDelta)
+
+	v.PutValidator(newTestValidator(subnetID, math.MaxUint64))
+
+	_, _, err = v.SimulateAddValidator(subnetID, 1)
+	require.ErrorIs(err, safemath.ErrOverflow)
+}
+
+func TestBaseStakersStakeQuantiles(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, err := v.StakeQuantiles(subnetID, []float64{0.5})
+	require.ErrorIs(err, errNoValidators)
+
+	// Insert the weights 10, 20, ..., 100 out of order.
+	for _, weight := range []uint64{50, 100, 10, 80, 30, 60, 20, 90, 40, 70} {
+		v.PutValidator(newTestValidator(subnetID, weight))
+	}
+
+	_, err = v.StakeQuantiles(subnetID, []float64{1.5})
+	require.ErrorIs(err, errInvalidQuantile)
+
+	_, err = v.StakeQuantiles(subnetID, []float64{-0.1})
+	require.ErrorIs(err, errInvalidQuantile)
+
+	quantiles, err := v.StakeQuantiles(subnetID, []float64{0, 0.5, 0.9, 1})
+	require.NoError(err)
+	require.Equal(
+		[]uint64{
+			10,  // minimum
+			55,  // interpolated between 50 and 60
+			91,  // interpolated between 90 and 100
+			100, // maximum
+		},
+		quantiles,
+	)
+}
+
+func TestBaseStakersWeightedMedianReward(t *testing.T) {
+	tests := []struct {
+		name           string
+		validators     []*Staker // Weight and PotentialReward are used
+		expectedReward uint64
+		expectedErr    error
+	}{
+		{
+			name:        "no validators",
+			expectedErr: errNoValidators,
+		},
+		{
+			name: "zero weight",
+			validators: []*Staker{
+				{Weight: 0, PotentialReward: 10},
+			},
+			expectedErr: errZeroWeight,
+		},
+		{
+			name: "single validator",
+			validators: []*Staker{
+				{Weight: 5, PotentialReward: 10},
+			},
+			expectedReward: 10,
+		},
+		{
+			name: "heavy validator dominates",
+			validators: []*Staker{
+				{Weight: 10, PotentialReward: 1},
+				{Weight: 70, PotentialReward: 100},
+				{Weight: 10, PotentialReward: 2},
+				{Weight: 10, PotentialReward: 3},
+			},
+			expectedReward: 100,
+		},
+		{
+			name: "median inside middle validator",
+			validators: []*Staker{
+				{Weight: 30, PotentialReward: 300},
+				{Weight: 20, PotentialReward: 100},
+				{Weight: 25, PotentialReward: 200},
+				{Weight: 25, PotentialReward: 400},
+			},
+			expectedReward: 300,
+		},
+		{
+			name: "median between validators",
+			validators: []*Staker{
+				{Weight: 50, PotentialReward: 20},
+				{Weight: 50, PotentialReward: 10},
+			},
+			expectedReward: 10,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			subnetID := ids.GenerateTestID()
+
+			v := newBaseStakers()
+			for _, validator := range test.validators {
+				staker := newTestValidator(subnetID, validator.Weight)
+				staker.PotentialReward = validator.PotentialReward
+				v.PutValidator(staker)
+			}
+
+			// Validators on other subnets must not be considered.
+			other := newTestStaker()
+			other.PotentialReward = 1_000_000
+			v.PutValidator(other)
+
+			reward, err := v.WeightedMedianReward(subnetID)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expectedReward, reward)
+		})
+	}
+}
+
+func TestBaseStakersParticipationRate(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	_, err := v.ParticipationRate(subnetID, 0)
+	require.ErrorIs(err, errZeroSupply)
+
+	rate, err := v.ParticipationRate(subnetID, 1000)
+	require.NoError(err)
+	require.Zero(rate)
+
+	validator := newTestValidator(subnetID, 200)
+	v.PutValidator(validator)
+
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validator.NodeID
+	delegator.Weight = 50
+	v.PutDelegator(delegator)
+
+	// Stakers on other subnets must not be counted.
+	other := newTestStaker()
+	other.Weight = 500
+	v.PutValidator(other)
+
+	rate, err = v.ParticipationRate(subnetID, 1000)
+	require.NoError(err)
+	require.InDelta(0.25, rate, floatDelta)
+}
+
+func TestBaseStakersProjectedStakeAfter(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	now := time.Unix(1_000_000, 0)
+	projectedStake, err := v.ProjectedStakeAfter(subnetID, now, genesistest.DefaultValidatorDuration)
+	require.NoError(err)
+	require.Zero(projectedStake)
+
+	newValidator := func(weight uint64, duration time.Duration) *Staker {
+		validator := newTestValidator(subnetID, weight)
+		validator.StartTime = now
+		validator.EndTime = now.Add(duration)
+		validator.NextTime = validator.EndTime
+		v.PutValidator(validator)
+		return validator
+	}
+
+	// Expires within the default duration.
+	_ = newValidator(10, genesistest.DefaultValidatorDuration/2)
+	// Expires exactly at the end of the default duration.
+	_ = newValidator(20, genesistest.DefaultValidatorDuration)
+	// Expires beyond the default duration.
+	remaining := newValidator(40, 2*genesistest.DefaultValidatorDuration)
+
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = remaining.NodeID
+	delegator.Weight = 5
+	delegator.EndTime = remaining.EndTime
+	delegator.NextTime = remaining.EndTime
+	v.PutDelegator(delegator)
+
+	// Pending stakers must not be counted.
+	pending := newTestValidator(subnetID, 80)
+	pending.EndTime = now.Add(3 * genesistest.DefaultValidatorDuration)
+	pending.Priority = txs.SubnetPermissionedValidatorPendingPriority
+	v.PutValidator(pending)
+
+	projectedStake, err = v.ProjectedStakeAfter(subnetID, now, genesistest.DefaultValidatorDuration)
+	require.NoError(err)
+	require.Equal(uint64(45), projectedStake)
+
+	projectedStake, err = v.ProjectedStakeAfter(subnetID, now, 0)
+	require.NoError(err)
+	require.Equal(uint64(75), projectedStake)
+}
+
+func TestBaseStakersSlashValidator(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	slashedValidator := newTestValidator(subnetID, 1)
+	honestValidator := newTestValidator(subnetID, 1)
+
+	v := newBaseStakers()
+
+	err := v.SlashValidator(subnetID, slashedValidator.NodeID)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	v.PutValidator(slashedValidator)
+	v.PutValidator(honestValidator)
+
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.GetSlashedValidators(subnetID))
+
+	require.NoError(v.SlashValidator(subnetID, slashedValidator.NodeID))
+
+	validator, err := v.GetValidator(subnetID, slashedValidator.NodeID)
+	require.NoError(err)
+	require.True(validator.Slashed)
+	require.Equal(uint64(1), validator.PotentialReward)
+	require.NoError(v.VerifyTotals())
+
+	// The original staker must not be modified.
+	require.False(slashedValidator.Slashed)
+	require.Equal(uint64(1), slashedValidator.PotentialReward)
+
+	validator, err = v.GetValidator(subnetID, honestValidator.NodeID)
+	require.NoError(err)
+	require.False(validator.Slashed)
+	require.Equal(uint64(1), validator.PotentialReward)
+
+	slashed, err := v.GetValidator(subnetID, slashedValidator.NodeID)
+	require.NoError(err)
+	assertIteratorsEqual(t, iterator.FromSlice(slashed), v.GetSlashedValidators(subnetID))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.GetSlashedValidators(ids.GenerateTestID()))
+	require.Equal(2, v.stakers.Len())
+}
+
+func TestBaseStakersDelegationHeadroom(t *testing.T) {
+	require := require.New(t)
+	validator := newTestValidator(ids.GenerateTestID(), 10)
+
+	v := newBaseStakers()
+
+	_, err := v.DelegationHeadroom(validator.SubnetID, validator.NodeID, 5)
+	require.ErrorIs(err, database.ErrNotFound)
+
+	v.PutValidator(validator)
+
+	// Without any delegation, the headroom is (5 - 1) * 10.
+	headroom, err := v.DelegationHeadroom(validator.SubnetID, validator.NodeID, 5)
+	require.NoError(err)
+	require.Equal(uint64(40), headroom)
+
+	delegator := newTestStaker()
+	delegator.SubnetID = validator.SubnetID
+	delegator.NodeID = validator.NodeID
+	delegator.Weight = 39
+	v.PutDelegator(delegator)
+
+	headroom, err = v.DelegationHeadroom(validator.SubnetID, validator.NodeID, 5)
+	require.NoError(err)
+	require.Equal(uint64(1), headroom)
+
+	// A validator that is at, or over, the cap has no headroom.
+	headroom, err = v.DelegationHeadroom(validator.SubnetID, validator.NodeID, 1)
+	require.NoError(err)
+	require.Zero(headroom)
+}
+
+func TestBaseStakersDelegatableValidators(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	open := newTestValidator(subnetID, 10)
+	v.PutValidator(open)
+	now := open.StartTime
+
+	// [full] has already reached 5x its own weight.
+	full := newTestValidator(subnetID, 10)
+	v.PutValidator(full)
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = full.NodeID
+	delegator.Weight = 40
+	v.PutDelegator(delegator)
+
+	notStarted := newTestValidator(subnetID, 10)
+	notStarted.StartTime = now.Add(time.Second)
+	v.PutValidator(notStarted)
+
+	ended := newTestValidator(subnetID, 10)
+	ended.EndTime = now
+	ended.NextTime = now
+	v.PutValidator(ended)
+
+	pending := newTestValidator(subnetID, 10)
+	pending.Priority = txs.SubnetPermissionedValidatorPendingPriority
+	v.PutValidator(pending)
+
+	// Validators on other subnets must not be returned.
+	v.PutValidator(newTestValidator(ids.GenerateTestID(), 10))
+
+	assertIteratorsEqual(t, iterator.FromSlice(open), v.DelegatableValidators(subnetID, now, 5))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.DelegatableValidators(subnetID, now, 1))
+}
+
+func TestBaseStakersFindRewardAnomalies(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	newValidator := func(reward uint64, delegatorRewards ...uint64) *Staker {
+		validator := newTestValidator(subnetID, 1)
+		validator.PotentialReward = reward
+		v.PutValidator(validator)
+		for _, delegatorReward := range delegatorRewards {
+			delegator := newTestStaker()
+			delegator.SubnetID = subnetID
+			delegator.NodeID = validator.NodeID
+			delegator.PotentialReward = delegatorReward
+			v.PutDelegator(delegator)
+		}
+		return validator
+	}
+
+	var (
+		_         = newValidator(10, 5, 5)
+		anomalous = newValidator(100, 5, 5)
+		_         = newValidator(100)
+	)
+
+	assertIteratorsEqual(t, iterator.FromSlice(anomalous), v.FindRewardAnomalies(subnetID, 2))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.FindRewardAnomalies(subnetID, 10))
+}
+
+func TestBaseStakersCommonValidators(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetA      = ids.GenerateTestID()
+		subnetB      = ids.GenerateTestID()
+		subnetC      = ids.GenerateTestID()
+		commonNodeID = ids.GenerateTestNodeID()
+	)
+
+	v := newBaseStakers()
+	require.Empty(v.CommonValidators(subnetA, subnetB))
+
+	for _, subnetID := range []ids.ID{subnetA, subnetB} {
+		validator := newTestValidator(subnetID, 1)
+		validator.NodeID = commonNodeID
+		v.PutValidator(validator)
+
+		v.PutValidator(newTestValidator(subnetID, 1))
+	}
+	v.PutValidator(newTestValidator(subnetC, 1))
+
+	// A delegator on a subnet doesn't make the node a validator of it.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetC
+	delegator.NodeID = commonNodeID
+	v.PutDelegator(delegator)
+
+	require.Equal([]ids.NodeID{commonNodeID}, v.CommonValidators(subnetA, subnetB))
+	require.Empty(v.CommonValidators(subnetA, subnetC))
+}
+
+func TestBaseStakersContinuousValidationDuration(t *testing.T) {
+	require := require.New(t)
+	validator := newTestStaker()
+	validator.StartTime = time.Unix(100, 0)
+
+	v := newBaseStakers()
+
+	_, err := v.ContinuousValidationDuration(validator.SubnetID, validator.NodeID, time.Unix(100, 0))
+	require.ErrorIs(err, database.ErrNotFound)
+
+	v.PutValidator(validator)
+
+	duration, err := v.ContinuousValidationDuration(validator.SubnetID, validator.NodeID, time.Unix(100, 0))
+	require.NoError(err)
+	require.Zero(duration)
+
+	duration, err = v.ContinuousValidationDuration(validator.SubnetID, validator.NodeID, time.Unix(160, 0))
+	require.NoError(err)
+	require.Equal(time.Minute, duration)
+
+	duration, err = v.ContinuousValidationDuration(validator.SubnetID, validator.NodeID, time.Unix(220, 0))
+	require.NoError(err)
+	require.Equal(2*time.Minute, duration)
+}
+
+func TestBaseStakersFindValidatorsWithoutDelegators(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.FindValidatorsWithoutDelegators(subnetID))
+
+	validators := make([]*Staker, 4)
+	for i := range validators {
+		validators[i] = newTestValidator(subnetID, 1)
+		validators[i].NextTime = validators[i].NextTime.Add(time.Duration(i) * time.Second)
+		v.PutValidator(validators[i])
+	}
+
+	// Delegate to the first and third validators.
+	for _, validator := range []*Staker{validators[0], validators[2]} {
+		delegator := newTestStaker()
+		delegator.SubnetID = subnetID
+		delegator.NodeID = validator.NodeID
+		v.PutDelegator(delegator)
+	}
+
+	// Validators on other subnets must not be returned.
+	v.PutValidator(newTestStaker())
+
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(validators[1], validators[3]),
+		v.FindValidatorsWithoutDelegators(subnetID),
+	)
+}
+
+func TestBaseStakersRewardPayoutSchedule(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+	require.Empty(v.RewardPayoutSchedule(subnetID))
+
+	var (
+		validators = make([]*Staker, 3)
+		endTimes   = []time.Time{
+			time.Unix(300, 0),
+			time.Unix(100, 0),
+			time.Unix(200, 0),
+		}
+	)
+	for i, endTime := range endTimes {
+		validators[i] = newTestValidator(subnetID, 1)
+		validators[i].EndTime = endTime
+		validators[i].NextTime = endTime
+		validators[i].PotentialReward = uint64(i + 1)
+		v.PutValidator(validators[i])
+	}
+
+	// Delegators and validators of other subnets must not be included.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validators[0].NodeID
+	v.PutDelegator(delegator)
+	v.PutValidator(newTestStaker())
+
+	require.Equal(
+		[]RewardEvent{
+			{
+				EndTime:         time.Unix(100, 0),
+				NodeID:          validators[1].NodeID,
+				PotentialReward: 2,
+			},
+			{
+				EndTime:         time.Unix(200, 0),
+				NodeID:          validators[2].NodeID,
+				PotentialReward: 3,
+			},
+			{
+				EndTime:         time.Unix(300, 0),
+				NodeID:          validators[0].NodeID,
+				PotentialReward: 1,
+			},
+		},
+		v.RewardPayoutSchedule(subnetID),
+	)
+}
+
+func TestBaseStakersIsPrimaryNetwork(t *testing.T) {
+	require := require.New(t)
+
+	v := newBaseStakers()
+	require.True(v.IsPrimaryNetwork(constants.PrimaryNetworkID))
+	require.False(v.IsPrimaryNetwork(ids.GenerateTestID()))
+}
+
+func TestBaseStakersNearestValidatorEndTime(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+	target := time.Unix(1000, 0)
+
+	v := newBaseStakers()
+
+	_, err := v.NearestValidatorEndTime(subnetID, target)
+	require.ErrorIs(err, errNoValidators)
+
+	newValidator := func(endTime time.Time) *Staker {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		validator.NextTime = endTime
+		v.PutValidator(validator)
+		return validator
+	}
+
+	newValidator(target.Add(-time.Hour))
+	after := newValidator(target.Add(10 * time.Minute))
+	newValidator(target.Add(time.Hour))
+
+	nearest, err := v.NearestValidatorEndTime(subnetID, target)
+	require.NoError(err)
+	require.Equal(after, nearest)
+
+	// Equidistant validators are broken towards the earlier one.
+	before := newValidator(target.Add(-10 * time.Minute))
+
+	nearest, err = v.NearestValidatorEndTime(subnetID, target)
+	require.NoError(err)
+	require.Equal(before, nearest)
+
+	// Validators on other subnets must be ignored.
+	other := newTestStaker()
+	other.EndTime = target
+	other.NextTime = target
+	v.PutValidator(other)
+
+	nearest, err = v.NearestValidatorEndTime(subnetID, target)
+	require.NoError(err)
+	require.Equal(before, nearest)
+}
+
+func TestBaseStakersNodeDelegationAcrossSubnets(t *testing.T) {
+	require := require.New(t)
+	nodeID := ids.GenerateTestNodeID()
+
+	v := newBaseStakers()
+
+	delegations, err := v.NodeDelegationAcrossSubnets(nodeID)
+	require.NoError(err)
+	require.Empty(delegations)
+
+	var (
+		subnetA = ids.GenerateTestID()
+		subnetB = ids.GenerateTestID()
+		subnetC = ids.GenerateTestID()
+	)
+	for _, subnetID := range []ids.ID{subnetA, subnetB, subnetC} {
+		validator := newTestValidator(subnetID, 10)
+		validator.NodeID = nodeID
+		v.PutValidator(validator)
+	}
+
+	for subnetID, weights := range map[ids.ID][]uint64{
+		subnetA: {1, 2},
+		subnetB: {5},
+	} {
+		for _, weight := range weights {
+			delegator := newTestStaker()
+			delegator.SubnetID = subnetID
+			delegator.NodeID = nodeID
+			delegator.Weight = weight
+			v.PutDelegator(delegator)
+		}
+	}
+
+	// Delegations to other nodes must not be counted.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetA
+	v.PutDelegator(delegator)
+
+	delegations, err = v.NodeDelegationAcrossSubnets(nodeID)
+	require.NoError(err)
+	require.Equal(
+		map[ids.ID]uint64{
+			subnetA: 3,
+			subnetB: 5,
+		},
+		delegations,
+	)
+}
+
+func TestBaseStakersFindPendingNextTimeMismatches(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	newPendingStaker := func() *Staker {
+		staker := newTestStaker()
+		staker.SubnetID = subnetID
+		staker.Priority = txs.SubnetPermissionedValidatorPendingPriority
+		staker.NextTime = staker.StartTime
+		return staker
+	}
+
+	v := newBaseStakers()
+
+	consistent := newPendingStaker()
+	v.PutValidator(consistent)
+
+	consistentDelegator := newPendingStaker()
+	consistentDelegator.NodeID = consistent.NodeID
+	consistentDelegator.Priority = txs.PrimaryNetworkDelegatorApricotPendingPriority
+	v.PutDelegator(consistentDelegator)
+
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.FindPendingNextTimeMismatches(subnetID))
+
+	mismatched := newPendingStaker()
+	mismatched.NextTime = mismatched.EndTime
+	v.PutValidator(mismatched)
+
+	// Current stakers and stakers on other subnets must be ignored.
+	current := newTestValidator(subnetID, 1)
+	v.PutValidator(current)
+
+	otherSubnet := newPendingStaker()
+	otherSubnet.SubnetID = ids.GenerateTestID()
+	otherSubnet.NextTime = otherSubnet.EndTime
+	v.PutValidator(otherSubnet)
+
+	assertIteratorsEqual(t, iterator.FromSlice(mismatched), v.FindPendingNextTimeMismatches(subnetID))
+}
+
+func TestBaseStakersUnrewardedEndedValidators(t *testing.T) {
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+
+	newValidator := func(endTime time.Time) *Staker {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		validator.NextTime = endTime
+		return validator
+	}
+
+	v := newBaseStakers()
+
+	var (
+		endedEarlier = newValidator(now.Add(-time.Hour))
+		endedNow     = newValidator(now)
+		active       = newValidator(now.Add(time.Hour))
+	)
+	v.PutValidator(endedEarlier)
+	v.PutValidator(endedNow)
+	v.PutValidator(active)
+
+	// Ended delegators and validators of other subnets must be ignored.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = active.NodeID
+	delegator.EndTime = now.Add(-time.Hour)
+	delegator.NextTime = delegator.EndTime
+	v.PutDelegator(delegator)
+
+	other := newTestValidator(ids.GenerateTestID(), 1)
+	other.EndTime = now.Add(-time.Hour)
+	other.NextTime = other.EndTime
+	v.PutValidator(other)
+
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(endedEarlier, endedNow),
+		v.UnrewardedEndedValidators(subnetID, now),
+	)
+	assertIteratorsEqual(
+		t,
+		iterator.Empty[*Staker]{},
+		v.UnrewardedEndedValidators(subnetID, now.Add(-2*time.Hour)),
+	)
+}
+
+func TestBaseStakersFindInvalidTimeRangeStakers(t *testing.T) {
+	v := newBaseStakers()
+
+	validator := newTestStaker()
+	v.PutValidator(validator)
+
+	delegator := newTestStaker()
+	delegator.SubnetID = validator.SubnetID
+	delegator.NodeID = validator.NodeID
+	v.PutDelegator(delegator)
+
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.FindInvalidTimeRangeStakers())
+
+	// Malformed stakers are inserted directly, bypassing any validation.
+	endsAtStart := newTestStaker()
+	endsAtStart.EndTime = endsAtStart.StartTime
+	endsAtStart.NextTime = endsAtStart.EndTime.Add(-time.Second)
+	v.PutValidator(endsAtStart)
+
+	endsBeforeStart := newTestStaker()
+	endsBeforeStart.SubnetID = validator.SubnetID
+	endsBeforeStart.NodeID = validator.NodeID
+	endsBeforeStart.EndTime = endsBeforeStart.StartTime.Add(-time.Hour)
+	endsBeforeStart.NextTime = endsBeforeStart.EndTime
+	v.PutDelegator(endsBeforeStart)
+
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(endsBeforeStart, endsAtStart),
+		v.FindInvalidTimeRangeStakers(),
+	)
+}
+
+func TestBaseStakersFindPriorityMisalignedDelegators(t *testing.T) {
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	currentValidator := newTestValidator(subnetID, 1)
+	v.PutValidator(currentValidator)
+
+	pendingValidator := newTestValidator(subnetID, 1)
+	pendingValidator.Priority = txs.SubnetPermissionedValidatorPendingPriority
+	v.PutValidator(pendingValidator)
+
+	newDelegator := func(validator *Staker, priority txs.Priority) *Staker {
+		delegator := newTestStaker()
+		delegator.SubnetID = subnetID
+		delegator.NodeID = validator.NodeID
+		delegator.Priority = priority
+		v.PutDelegator(delegator)
+		return delegator
+	}
+
+	newDelegator(currentValidator, txs.SubnetPermissionlessDelegatorCurrentPriority)
+	newDelegator(pendingValidator, txs.SubnetPermissionlessDelegatorPendingPriority)
+
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.FindPriorityMisalignedDelegators(subnetID))
+
+	misaligned := newDelegator(currentValidator, txs.SubnetPermissionlessDelegatorPendingPriority)
+
+	assertIteratorsEqual(t, iterator.FromSlice(misaligned), v.FindPriorityMisalignedDelegators(subnetID))
+}
+
+func TestBaseStakersCanonicalValidatorSet(t *testing.T) {
+	require := require.New(t)
+	subnetID := ids.GenerateTestID()
+
+	v := newBaseStakers()
+
+	validators, totalWeight, err := v.CanonicalValidatorSet(subnetID)
+	require.NoError(err)
+	require.Empty(validators)
+	require.Zero(totalWeight)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+
+	var (
+		validatorA = newTestValidator(subnetID, 1)
+		validatorB = newTestValidator(subnetID, 2)
+		validatorC = newTestValidator(subnetID, 4)
+	)
+	validatorA.NodeID = ids.BuildTestNodeID([]byte{3})
+	validatorB.NodeID = ids.BuildTestNodeID([]byte{1})
+	validatorB.PublicKey = bls.PublicFromSecretKey(sk)
+	validatorC.NodeID = ids.BuildTestNodeID([]byte{2})
+	for _, validator := range []*Staker{validatorA, validatorB, validatorC} {
+		v.PutValidator(validator)
+	}
+
+	// Delegators must not be included.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = validatorA.NodeID
+	delegator.Weight = 8
+	v.PutDelegator(delegator)
+
+	validators, totalWeight, err = v.CanonicalValidatorSet(subnetID)
+	require.NoError(err)
+	require.Equal(
+		[]CanonicalValidator{
+			{
+				NodeID:    validatorB.NodeID,
+				Weight:    2,
+				PublicKey: validatorB.PublicKey,
+			},
+			{
+				NodeID: validatorC.NodeID,
+				Weight: 4,
+			},
+			{
+				NodeID: validatorA.NodeID,
+				Weight: 1,
+			},
+		},
+		validators,
+	)
+	require.Equal(uint64(7), totalWeight)
+}
+
+func TestBaseStakersValidatorsExpiringWith(t *testing.T) {
+	var (
+		subnetID = ids.GenerateTestID()
+		endTime  = time.Unix(1000, 0)
+	)
+
+	newValidator := func(endTime time.Time) *Staker {
+		validator := newTestValidator(subnetID, 1)
+		validator.EndTime = endTime
+		validator.NextTime = endTime
+		return validator
+	}
+
+	v := newBaseStakers()
+
+	target := newValidator(endTime)
+	v.PutValidator(target)
+
+	unique := newValidator(endTime.Add(time.Second))
+	v.PutValidator(unique)
+
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.ValidatorsExpiringWith(subnetID, target.TxID))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.ValidatorsExpiringWith(subnetID, ids.GenerateTestID()))
+
+	coExpiring := []*Staker{
+		newValidator(endTime),
+		newValidator(endTime),
+	}
+	for _, validator := range coExpiring {
+		v.PutValidator(validator)
+	}
+	slices.SortFunc(coExpiring, compareStakers)
+
+	// Delegators and validators of other subnets must not be returned.
+	delegator := newTestStaker()
+	delegator.SubnetID = subnetID
+	delegator.NodeID = target.NodeID
+	delegator.EndTime = endTime
+	v.PutDelegator(delegator)
+
+	other := newTestStaker()
+	other.EndTime = endTime
+	v.PutValidator(other)
+
+	assertIteratorsEqual(t, iterator.FromSlice(coExpiring...), v.ValidatorsExpiringWith(subnetID, target.TxID))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, v.ValidatorsExpiringWith(subnetID, unique.TxID))
+}
+
+func TestBaseStakersVerifyTotals(t *testing.T) {
+	require := require.New(t)
+	validator := newTestStaker()
+	delegator := newTestStaker()
+	delegator.SubnetID = validator.SubnetID
+	delegator.NodeID = validator.NodeID
+
+	v := newBaseStakers()
+	require.NoError(v.VerifyTotals())
+
+	v.PutValidator(validator)
+	v.PutDelegator(delegator)
+	require.NoError(v.VerifyTotals())
+	require.Equal(
+		stakerTotals{
+			numValidators:   1,
+			numDelegators:   1,
+			validatorWeight: validator.Weight,
+			delegatorWeight: delegator.Weight,
+			potentialReward: validator.PotentialReward + delegator.PotentialReward,
+		},
+		v.totals,
+	)
+
+	require.NoError(v.SlashValidator(validator.SubnetID, validator.NodeID))
+	require.NoError(v.VerifyTotals())
+
+	duplicate := *delegator
+	duplicate.NextTime = delegator.NextTime.Add(time.Second)
+	v.PutDelegator(&duplicate)
+	require.Equal(1, v.DeduplicateDelegators(validator.SubnetID, validator.NodeID))
+	require.NoError(v.VerifyTotals())
+
+	v.DeleteDelegator(delegator)
+	v.DeleteValidator(validator)
+	require.NoError(v.VerifyTotals())
+	require.Zero(v.totals)
+
+	// Corrupting a maintained counter must be detected.
+	v.PutValidator(validator)
+	v.totals.numDelegators++
+	require.ErrorIs(v.VerifyTotals(), errTotalsMismatch)
+}
+
+func TestBaseStakersVerifyTotalsDetectsCorruption(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(v *baseStakers, validator *Staker)
+	}{
+		{
+			name: "validator weight",
+			corrupt: func(v *baseStakers, _ *Staker) {
+				v.totals.validatorWeight++
+			},
+		},
+		{
+			name: "subnet delegator count",
+			corrupt: func(v *baseStakers, validator *Staker) {
+				v.subnetCounts[validator.SubnetID].delegators++
+			},
+		},
+		{
+			name: "subnet potential reward",
+			corrupt: func(v *baseStakers, validator *Staker) {
+				v.subnetCounts[validator.SubnetID].potentialReward.add(1)
+			},
+		},
+		{
+			name: "subnet counts of unknown subnet",
+			corrupt: func(v *baseStakers, _ *Staker) {
+				v.subnetCounts[ids.GenerateTestID()] = &subnetStakerCounts{validators: 1}
+			},
+		},
+		{
+			name: "validator missing from weight index",
+			corrupt: func(v *baseStakers, validator *Staker) {
+				v.validatorsByWeight[validator.SubnetID].Delete(validator)
+			},
+		},
+		{
+			name: "weight index of unknown subnet",
+			corrupt: func(v *baseStakers, _ *Staker) {
+				v.indexValidator(&Staker{SubnetID: ids.GenerateTestID()})
+			},
+		},
+		{
+			name: "activity of unknown subnet",
+			corrupt: func(v *baseStakers, _ *Staker) {
+				v.lastActivity[ids.GenerateTestID()] = time.Unix(1, 0)
+			},
+		},
+		{
+			name: "ordered stakers",
+			corrupt: func(v *baseStakers, _ *Staker) {
+				v.stakers.ReplaceOrInsert(newTestStaker())
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			validator := newTestStaker()
+			delegator := newTestStaker()
+			delegator.SubnetID = validator.SubnetID
+			delegator.NodeID = validator.NodeID
+
+			v := newBaseStakers()
+			v.PutValidator(validator)
+			v.PutDelegator(delegator)
+			require.NoError(v.VerifyTotals())
+
+			test.corrupt(v, validator)
+			require.ErrorIs(v.VerifyTotals(), errTotalsMismatch)
+		})
+	}
+}
+
+func TestStakerTotalsOverflow(t *testing.T) {
+	require := require.New(t)
+
+	var totals stakerTotals
+	totals.addValidator(&Staker{Weight: math.MaxUint64})
+	require.False(totals.overflowed)
+	require.Equal(uint64(math.MaxUint64), totals.validatorWeight)
+
+	totals.addValidator(&Staker{Weight: 1})
+	require.True(totals.overflowed)
+	require.Equal(uint64(math.MaxUint64), totals.validatorWeight)
+}
+
+func TestDiffStakersValidator(t *testing.T) {
+	require := require.New(t)
+	staker := newTestStaker()
+	delegator := newTestStaker()
+
+	v := diffStakers{}
+
+	v.PutDelegator(delegator)
+
+	// validators not available in the diff are marked as unmodified
+	_, status := v.GetValidator(ids.GenerateTestID(), delegator.NodeID)
+	require.Equal(unmodified, status)
+
+	_, status = v.GetValidator(delegator.SubnetID, ids.GenerateTestNodeID())
+	require.Equal(unmodified, status)
+
+	// delegator addition shouldn't change validatorStatus
+	_, status = v.GetValidator(delegator.SubnetID, delegator.NodeID)
+	require.Equal(unmodified, status)
+
+	stakerIterator := v.GetStakerIterator(iterator.Empty[*Staker]{})
+	assertIteratorsEqual(t, iterator.FromSlice(delegator), stakerIterator)
+
+	require.NoError(v.PutValidator(staker))
+
+	returnedStaker, status := v.GetValidator(staker.SubnetID, staker.NodeID)
+	require.Equal(added, status)
+	require.Equal(staker, returnedStaker)
+
+	v.DeleteValidator(staker)
+
+	// Validators created and deleted in the same diff are marked as unmodified.
+	// This means they won't be pushed to baseState if diff.Apply(baseState) is
+	// called.
+	_, status = v.GetValidator(staker.SubnetID, staker.NodeID)
+	require.Equal(unmodified, status)
+	require.NotContains(v.WeightDiffs()[staker.SubnetID], staker.NodeID)
+
+	stakerIterator = v.GetStakerIterator(iterator.Empty[*Staker]{})
+	assertIteratorsEqual(t, iterator.FromSlice(delegator), stakerIterator)
+}
+
+func TestGetEffectiveValidators(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID       = ids.GenerateTestID()
+		baseValidator  = newTestValidator(subnetID, 1)
+		deletedBase    = newTestValidator(subnetID, 2)
+		addedValidator = newTestValidator(subnetID, 3)
+		transient      = newTestValidator(subnetID, 4)
+		otherSubnet    = newTestValidator(ids.GenerateTestID(), 5)
+		unknownNodeID  = ids.GenerateTestNodeID()
+	)
+
+	base := newBaseStakers()
+	base.PutValidator(baseValidator)
+	base.PutValidator(deletedBase)
+	base.PutValidator(otherSubnet)
+
+	d := &diffStakers{}
+	d.DeleteValidator(deletedBase)
+	require.NoError(d.PutValidator(addedValidator))
+	require.NoError(d.PutValidator(transient))
+	d.DeleteValidator(transient)
+
+	validators := GetEffectiveValidators(
+		base,
+		d,
+		subnetID,
+		[]ids.NodeID{
+			baseValidator.NodeID,
+			deletedBase.NodeID,
+			addedValidator.NodeID,
+			transient.NodeID,
+			otherSubnet.NodeID,
+			unknownNodeID,
+		},
+	)
+	require.Equal(
+		map[ids.NodeID]*Staker{
+			baseValidator.NodeID:  baseValidator,
+			addedValidator.NodeID: addedValidator,
+		},
+		validators,
+	)
+}
+
+func TestStakeAtDiff(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID      = ids.GenerateTestID()
+		baseValidator = newTestValidator(subnetID, 10)
+		baseDelegator = newTestStaker()
+		otherSubnet   = newTestValidator(ids.GenerateTestID(), 100)
+	)
+	baseDelegator.SubnetID = subnetID
+	baseDelegator.NodeID = baseValidator.NodeID
+	baseDelegator.Weight = 5
+
+	base := newBaseStakers()
+	base.PutValidator(baseValidator)
+	base.PutDelegator(baseDelegator)
+	base.PutValidator(otherSubnet)
+
+	// Add a validator with a delegator.
+	addedValidator := newTestValidator(subnetID, 20)
+	addedDelegator := newTestStaker()
+	addedDelegator.SubnetID = subnetID
+	addedDelegator.NodeID = addedValidator.NodeID
+	addedDelegator.Weight = 7
+
+	first := &diffStakers{}
+	require.NoError(first.PutValidator(addedValidator))
+	first.PutDelegator(addedDelegator)
+
+	// Remove the base delegator and modify another subnet.
+	second := &diffStakers{}
+	second.DeleteDelegator(baseDelegator)
+	second.DeleteValidator(otherSubnet)
+
+	// Remove the base validator.
+	third := &diffStakers{}
+	third.DeleteValidator(baseValidator)
+
+	diffs := []*diffStakers{first, second, third}
+	for upTo, expectedStake := range []uint64{15, 42, 37, 27} {
+		stake, err := StakeAtDiff(base, diffs, upTo, subnetID)
+		require.NoError(err)
+		require.Equal(expectedStake, stake)
+	}
+
+	_, err := StakeAtDiff(base, diffs, len(diffs)+1, subnetID)
+	require.ErrorIs(err, errInvalidDiffIndex)
+
+	_, err = StakeAtDiff(base, diffs, -1, subnetID)
+	require.ErrorIs(err, errInvalidDiffIndex)
+
+	// Computing the stake must not modify the base.
+	stake, err := StakeAtDiff(base, diffs, 0, subnetID)
+	require.NoError(err)
+	require.Equal(uint64(15), stake)
+}
+
+func TestDiffBetween(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID            = ids.GenerateTestID()
+		unmodified          = newTestValidator(subnetID, 10)
+		deleted             = newTestValidator(subnetID, 20)
+		reweighted          = newTestValidator(subnetID, 30)
+		deletedDelegator    = newTestStaker()
+		movedDelegator      = newTestStaker()
+		unmodifiedDelegator = newTestStaker()
+	)
+	for _, delegator := range []*Staker{deletedDelegator, movedDelegator, unmodifiedDelegator} {
+		delegator.SubnetID = subnetID
+		delegator.NodeID = unmodified.NodeID
+	}
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	unmodified.PublicKey = bls.PublicFromSecretKey(sk)
+
+	oldStakers := newBaseStakers()
+	oldStakers.PutValidator(unmodified)
+	oldStakers.PutValidator(deleted)
+	oldStakers.PutValidator(reweighted)
+	oldStakers.PutDelegator(deletedDelegator)
+	oldStakers.PutDelegator(movedDelegator)
+	oldStakers.PutDelegator(unmodifiedDelegator)
+
+	var (
+		added          = newTestValidator(ids.GenerateTestID(), 40)
+		addedDelegator = newTestStaker()
+	)
+	addedDelegator.SubnetID = added.SubnetID
+	addedDelegator.NodeID = added.NodeID
+
+	reweightedCopy := *reweighted
+	reweightedCopy.Weight++
+	movedDelegatorCopy := *movedDelegator
+	movedDelegatorCopy.NextTime = movedDelegator.NextTime.Add(time.Hour)
+
+	newStakers := oldStakers.Clone()
+	newStakers.DeleteValidator(deleted)
+	newStakers.PutValidator(&reweightedCopy)
+	newStakers.DeleteDelegator(deletedDelegator)
+	newStakers.DeleteDelegator(movedDelegator)
+	newStakers.PutDelegator(&movedDelegatorCopy)
+	newStakers.PutValidator(added)
+	newStakers.PutDelegator(addedDelegator)
+
+	diff := DiffBetween(oldStakers, newStakers)
+
+	applied := oldStakers.Clone()
+	require.NoError(diff.ApplyWithBatchedEvents(applied, func([]StakerEvent) error {
+		return nil
+	}))
+
+	// Only the stakers are expected to match, not how they were modified.
+	applied.validatorDiffs = nil
+	newStakers.validatorDiffs = nil
+	requireBaseStakersEqual(t, newStakers, applied)
+
+	// Identical stakers result in an empty diff.
+	diff = DiffBetween(oldStakers, oldStakers)
+	require.Empty(diff.validatorDiffs)
+
+	// Stakers that are equal but not identical result in an empty diff.
+	equalStakers := oldStakers.Clone()
+	unmodifiedCopy := *unmodified
+	unmodifiedCopy.EndTime = unmodified.EndTime.UTC()
+	unmodifiedCopy.PublicKey = bls.PublicKeyFromValidUncompressedBytes(
+		bls.PublicKeyToUncompressedBytes(unmodified.PublicKey),
+	)
+	equalStakers.PutValidator(&unmodifiedCopy)
+
+	diff = DiffBetween(oldStakers, equalStakers)
+	require.Empty(diff.validatorDiffs)
+
+	// A validator replaced by a different staker must be replaced when the
+	// diff is applied.
+	replaced := *unmodified
+	replaced.TxID = ids.GenerateTestID()
+	replacedStakers := oldStakers.Clone()
+	replacedStakers.DeleteValidator(unmodified)
+	replacedStakers.PutValidator(&replaced)
+
+	diff = DiffBetween(oldStakers, replacedStakers)
+	applied = oldStakers.Clone()
+	require.NoError(diff.ApplyWithBatchedEvents(applied, func([]StakerEvent) error {
+		return nil
+	}))
+
+	applied.validatorDiffs = nil
+	replacedStakers.validatorDiffs = nil
+	requireBaseStakersEqual(t, replacedStakers, applied)
+}
+
+func TestGetLayeredStakerIterator(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		baseOnly          = newTestStaker()
+		deletedFromBase   = newTestStaker()
+		readded           = newTestStaker()
+		addedValidator    = newTestStaker()
+		addedDelegator    = newTestStaker()
+		laterDeleted      = newTestStaker()
+		sameLayerDeleted  = newTestStaker()
+		replacedDelegator = newTestStaker()
+	)
+	readdedCopy := *readded
+	readdedCopy.NextTime = readded.NextTime.Add(time.Hour)
+	replacingDelegator := *replacedDelegator
+	replacingDelegator.NextTime = replacedDelegator.NextTime.Add(time.Hour)
+
+	base := newBaseStakers()
+	base.PutValidator(baseOnly)
+	base.PutValidator(deletedFromBase)
+	base.PutValidator(readded)
+
+	layer0 := &diffStakers{}
+	layer0.DeleteValidator(deletedFromBase)
+	layer0.DeleteValidator(readded)
+	require.NoError(layer0.PutValidator(addedValidator))
+	layer0.PutDelegator(addedDelegator)
+	layer0.PutDelegator(replacedDelegator)
+
+	// Stakers deleted in one layer can be re-added by a newer layer.
+	layer1 := &diffStakers{}
+	require.NoError(layer1.PutValidator(&readdedCopy))
+	require.NoError(layer1.PutValidator(laterDeleted))
+	layer1.PutDelegator(sameLayerDeleted)
+	layer1.DeleteDelegator(sameLayerDeleted)
+
+	// Stakers added in one layer can be deleted or replaced by a newer layer.
+	layer2 := &diffStakers{}
+	layer2.DeleteValidator(laterDeleted)
+	layer2.PutDelegator(&replacingDelegator)
+
+	expected := []*Staker{
+		baseOnly,
+		addedValidator,
+		addedDelegator,
+		&readdedCopy,
+		&replacingDelegator,
+	}
+	slices.SortFunc(expected, compareStakers)
+
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(expected...),
+		GetLayeredStakerIterator(
+			base.GetStakerIterator(),
+			[]*diffStakers{layer0, layer1, layer2},
+		),
+	)
+
+	// Without any layers the parent iterator is returned unmodified.
+	assertIteratorsEqual(
+		t,
+		base.GetStakerIterator(),
+		GetLayeredStakerIterator(base.GetStakerIterator(), nil),
+	)
+}
+
+func TestDiffStakersWeightDiffs(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		subnetID         = ids.GenerateTestID()
+		addedValidator   = newTestValidator(subnetID, 10)
+		deletedValidator = newTestValidator(subnetID, 20)
+		transient        = newTestValidator(subnetID, 30)
+		delegated        = newTestValidator(subnetID, 40)
+	)
+	newDelegator := func(validator *Staker, weight uint64) *Staker {
+		delegator := newTestStaker()
+		delegator.SubnetID = validator.SubnetID
+		delegator.NodeID = validator.NodeID
+		delegator.Weight = weight
+		return delegator
+	}
+
+	v := diffStakers{}
+	require.Empty(v.WeightDiffs())
+
+	// Added validators increase the weight by their own weight and the weight
+	// of any delegators added in the same diff.
+	require.NoError(v.PutValidator(addedValidator))
+	v.PutDelegator(newDelegator(addedValidator, 1))
+
+	// Deleted validators decrease the weight by their own weight and the
+	// weight of any deleted delegators.
+	v.DeleteValidator(deletedValidator)
+	v.DeleteDelegator(newDelegator(deletedValidator, 2))
+
+	// Validators added and deleted in the same diff only record the changes
+	// of their delegators.
+	require.NoError(v.PutValidator(transient))
+	transientDelegator := newDelegator(transient, 3)
+	v.PutDelegator(transientDelegator)
+	v.DeleteValidator(transient)
+
+	// Delegator changes on unmodified validators are netted.
+	v.PutDelegator(newDelegator(delegated, 5))
+	v.DeleteDelegator(newDelegator(delegated, 7))
+
+	// Deleting the same delegator twice must only count it once.
+	removedDelegator := newDelegator(delegated, 1)
+	v.DeleteDelegator(removedDelegator)
+	v.DeleteDelegator(removedDelegator)
+
+	require.Equal(
+		map[ids.ID]map[ids.NodeID]*ValidatorWeightDiff{
+			subnetID: {
+				addedValidator.NodeID: {
+					Decrease: false,
+					Amount:   11,
+				},
+				deletedValidator.NodeID: {
+					Decrease: true,
+					Amount:   22,
+				},
+				transient.NodeID: {
+					Decrease: false,
+					Amount:   3,
+				},
+				delegated.NodeID: {
+					Decrease: true,
+					Amount:   3,
+				},
+			},
+		},
+		v.WeightDiffs(),
+	)
+
+	// Netting out all changes must remove the validator from the diffs.
+	v.DeleteDelegator(transientDelegator)
+	require.NotContains(v.WeightDiffs()[subnetID], transient.NodeID)
+
+	// Modifying the returned diffs must not modify the diff.
+	v.WeightDiffs()[subnetID][addedValidator.NodeID].Amount = 0
+	require.Equal(uint64(11), v.WeightDiffs()[subnetID][addedValidator.NodeID].Amount)
+}
+
+func TestDiffStakersReverseIterator(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		baseTime = time.Unix(1000, 0)
+		stakers  = newReverseIteratorTestStakers(baseTime)
+	)
+
+	base := newBaseStakers()
+	base.PutValidator(stakers.firstValidator)
+	base.PutValidator(stakers.tiedValidator)
+
+	v := diffStakers{}
+	v.PutDelegator(stakers.tiedDelegator)
+	require.NoError(v.PutValidator(stakers.lastValidator))
+	v.DeleteValidator(stakers.firstValidator)
+
+	stakerIterator := v.GetStakerReverseIterator(base.GetStakerReverseIterator())
+	assertIteratorsEqual(
+		t,
+		iterator.FromSlice(
+			stakers.lastValidator,
+			stakers.tiedValidator,
+			stakers.tiedDelegator,
+		),
+		stakerIterator,
+	)
+}
+
+func TestDiffStakersDeleteValidator(t *testing.T) {
+	require := require.New(t)
+	staker := newTestStaker()
+	delegator := newTestStaker()
+
+	v := diffStakers{}
+
+	_, status := v.GetValidator(ids.GenerateTestID(), delegator.NodeID)
+	require.Equal(unmodified, status)
+
+	v.DeleteValidator(staker)
+
+	returnedStaker, status := v.GetValidator(staker.SubnetID, staker.NodeID)
+	require.Equal(deleted, status)
+	require.Nil(returnedStaker)
+}
+
+func TestDiffStakersDelegator(t *testing.T) {
+	staker := newTestStaker()
+	delegator := newTestStaker()
+
+	v := diffStakers{}
+
+	require.NoError(t, v.PutValidator(staker))
+
+	delegatorIterator := v.GetDelegatorIterator(iterator.Empty[*Staker]{}, ids.GenerateTestID(), delegator.NodeID)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+
+	v.PutDelegator(delegator)
+
+	delegatorIterator = v.GetDelegatorIterator(iterator.Empty[*Staker]{}, delegator.SubnetID, delegator.NodeID)
+	assertIteratorsEqual(t, iterator.FromSlice(delegator), delegatorIterator)
+
+	v.DeleteDelegator(delegator)
+
+	delegatorIterator = v.GetDelegatorIterator(iterator.Empty[*Staker]{}, ids.GenerateTestID(), delegator.NodeID)
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, delegatorIterator)
+}
+
+func TestDiffStakersNumDelegators(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		validator        = newTestStaker()
+		deletedDelegator = newTestStaker()
+		addedDelegator   = newTestStaker()
+	)
+	deletedDelegator.SubnetID = validator.SubnetID
+	deletedDelegator.NodeID = validator.NodeID
+	addedDelegator.SubnetID = validator.SubnetID
+	addedDelegator.NodeID = validator.NodeID
+
+	v := diffStakers{}
+	require.Equal(2, v.NumDelegators(2, validator.SubnetID, validator.NodeID))
+
+	v.DeleteDelegator(deletedDelegator)
+	require.Equal(1, v.NumDelegators(2, validator.SubnetID, validator.NodeID))
+
+	v.PutDelegator(addedDelegator)
+	require.Equal(2, v.NumDelegators(2, validator.SubnetID, validator.NodeID))
+
+	// A delegator added and deleted in the same diff must not be counted.
+	v.DeleteDelegator(addedDelegator)
+	require.Equal(1, v.NumDelegators(2, validator.SubnetID, validator.NodeID))
+
+	require.Equal(2, v.NumDelegators(2, validator.SubnetID, ids.GenerateTestNodeID()))
+}
+
+func TestDiffStakersApplyStreaming(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		baseValidator    = newTestStaker()
+		deletedDelegator = newTestStaker()
+		addedValidator   = newTestStaker()
+		addedDelegator   = newTestStaker()
+	)
+	deletedDelegator.SubnetID = baseValidator.SubnetID
+	deletedDelegator.NodeID = baseValidator.NodeID
+	addedDelegator.SubnetID = addedValidator.SubnetID
+	addedDelegator.NodeID = addedValidator.NodeID
+
+	base := newBaseStakers()
+	base.PutValidator(baseValidator)
+	base.PutDelegator(deletedDelegator)
+
+	v := diffStakers{}
+	v.DeleteValidator(baseValidator)
+	v.DeleteDelegator(deletedDelegator)
+	require.NoError(v.PutValidator(addedValidator))
+	v.PutDelegator(addedDelegator)
+
+	// Validators added and deleted in the same diff must not emit events.
+	transientValidator := newTestStaker()
+	require.NoError(v.PutValidator(transientValidator))
+	v.DeleteValidator(transientValidator)
+
+	events := make(chan StakerEvent, 4)
+	require.NoError(v.ApplyStreaming(base, events))
+	require.Len(events, 4)
+
+	var emitted []StakerEvent
+	for len(events) > 0 {
+		emitted = append(emitted, <-events)
+	}
+	require.Equal(
+		sortedStakerEvents(
+			StakerEvent{Type: ValidatorDeleted, Staker: baseValidator},
+			StakerEvent{Type: DelegatorDeleted, Staker: deletedDelegator},
+			StakerEvent{Type: ValidatorAdded, Staker: addedValidator},
+			StakerEvent{Type: DelegatorAdded, Staker: addedDelegator},
+		),
+		emitted,
+	)
+
+	// The channel must not have been closed.
+	events <- StakerEvent{}
+
+	stakerIterator := base.GetStakerIterator()
+	assertIteratorsEqual(t, v.GetStakerIterator(iterator.Empty[*Staker]{}), stakerIterator)
+}
+
+func TestDiffStakersApplyAddedAndDeletedDelegator(t *testing.T) {
+	require := require.New(t)
+
+	delegator := newTestStaker()
+
+	v := diffStakers{}
+	v.PutDelegator(delegator)
+	v.DeleteDelegator(delegator)
+
+	base := newBaseStakers()
+	require.NoError(v.ApplyWithBatchedEvents(base, func(events []StakerEvent) error {
+		// The deletion takes precedence, so it must be applied last.
+		require.Equal(
+			[]StakerEvent{
+				{Type: DelegatorAdded, Staker: delegator},
+				{Type: DelegatorDeleted, Staker: delegator},
+			},
+			events,
+		)
+		return nil
+	}))
+	require.Zero(base.NumDelegators(delegator.SubnetID, delegator.NodeID))
+	assertIteratorsEqual(t, iterator.Empty[*Staker]{}, base.GetStakerIterator())
+}
+
+func TestDiffStakersApplyWithBatchedEvents(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		baseValidator    = newTestStaker()
+		deletedDelegator = newTestStaker()
+		addedValidator   = newTestStaker()
+		addedDelegator   = newTestStaker()
+	)
+	deletedDelegator.SubnetID = baseValidator.SubnetID
+	deletedDelegator.NodeID = baseValidator.NodeID
+	addedDelegator.SubnetID = addedValidator.SubnetID
+	addedDelegator.NodeID = addedValidator.NodeID
+
+	base := newBaseStakers()
+	base.PutValidator(baseValidator)
+	base.PutDelegator(deletedDelegator)
+
+	// Stakers that are untouched by the diff must not emit events.
+	base.PutValidator(newTestStaker())
+
+	v := diffStakers{}
+	v.DeleteValidator(baseValidator)
+	v.DeleteDelegator(deletedDelegator)
+	require.NoError(v.PutValidator(addedValidator))
+	v.PutDelegator(addedDelegator)
+
+	// Validators added and deleted in the same diff must not emit events.
+	transientValidator := newTestStaker()
+	require.NoError(v.PutValidator(transientValidator))
+	v.DeleteValidator(transientValidator)
+
+	var (
+		numCalls int
+		batch    []StakerEvent
+	)
+	require.NoError(v.ApplyWithBatchedEvents(base, func(events []StakerEvent) error {
+		numCalls++
+		batch = events
+		return nil
+	}))
+	require.Equal(1, numCalls)
+	require.Equal(
+		sortedStakerEvents(
+			StakerEvent{Type: ValidatorDeleted, Staker: baseValidator},
+			StakerEvent{Type: DelegatorDeleted, Staker: deletedDelegator},
+			StakerEvent{Type: ValidatorAdded, Staker: addedValidator},
+			StakerEvent{Type: DelegatorAdded, Staker: addedDelegator},
+		),
+		batch,
+	)
+
+	errTest := errors.New("non-nil error")
+	err := v.ApplyWithBatchedEvents(newBaseStakers(), func([]StakerEvent) error {
+		return errTest
+	})
+	require.ErrorIs(err, errTest)
+}
+
+type reverseIteratorTestStakers struct {
+	firstValidator *Staker
+	tiedDelegator  *Staker
+	tiedValidator  *Staker
+	lastValidator  *Staker
+}
+
+// newReverseIteratorTestStakers returns stakers where a validator and its
+// delegator share a NextTime.
+func newReverseIteratorTestStakers(baseTime time.Time) reverseIteratorTestStakers {
+	newStaker := func(nextTime time.Time, priority txs.Priority) *Staker {
+		staker := newTestStaker()
+		staker.SubnetID = constants.PrimaryNetworkID
+		staker.NextTime = nextTime
+		staker.Priority = priority
+		return staker
+	}
+
+	stakers := reverseIteratorTestStakers{
+		firstValidator: newStaker(baseTime, txs.PrimaryNetworkValidatorCurrentPriority),
+		tiedDelegator:  newStaker(baseTime.Add(time.Second), txs.PrimaryNetworkDelegatorCurrentPriority),
+		tiedValidator:  newStaker(baseTime.Add(time.Second), txs.PrimaryNetworkValidatorCurrentPriority),
+		lastValidator:  newStaker(baseTime.Add(2*time.Second), txs.PrimaryNetworkValidatorCurrentPriority),
+	}
+	stakers.tiedDelegator.NodeID = stakers.tiedValidator.NodeID
+	return stakers
+}
+
+// sortedStakerEvents returns [events] in the order that they are emitted when
+// applying a diff.
+func sortedStakerEvents(events ...StakerEvent) []StakerEvent {
+	slices.SortFunc(events, func(a, b StakerEvent) int {
+		return compareStakers(a.Staker, b.Staker)
+	})
+	return events
+}
+
+func newTestStaker() *Staker {
+	startTime := time.Now().Round(time.Second)
+	endTime := startTime.Add(genesistest.DefaultValidatorDuration)
+	return &Staker{
+		TxID:            ids.GenerateTestID(),
+		NodeID:          ids.GenerateTestNodeID(),
+		SubnetID:        ids.GenerateTestID(),
+		Weight:          1,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		PotentialReward: 1,
+
+		NextTime: endTime,
+		Priority: txs.PrimaryNetworkDelegatorCurrentPriority,
+	}
+}
+
+func newTestValidator(subnetID ids.ID, weight uint64) *Staker {
+	staker := newTestStaker()
+	staker.SubnetID = subnetID
+	staker.Weight = weight
+	staker.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+	return staker
+}
+
+// requireBaseStakersEqual asserts that [expected] and [actual] contain the same
+// stakers and diffs.
+func requireBaseStakersEqual(t *testing.T, expected, actual *baseStakers) {
+	require := require.New(t)
+
+	t.Helper()
+
+	require.Equal(expected.totals, actual.totals)
+	require.Equal(expected.subnetCounts, actual.subnetCounts)
+	assertIteratorsEqual(t, expected.GetStakerIterator(), actual.GetStakerIterator())
+
+	require.Len(actual.validatorsByWeight, len(expected.validatorsByWeight))
+	for subnetID := range expected.validatorsByWeight {
+		assertIteratorsEqual(
+			t,
+			expected.GetValidatorsByWeight(subnetID, 0, math.MaxUint64),
+			actual.GetValidatorsByWeight(subnetID, 0, math.MaxUint64),
+		)
+	}
+
+	require.Len(actual.validators, len(expected.validators))
+	for subnetID, expectedValidators := range expected.validators {
+		actualValidators := actual.validators[subnetID]
+		require.Len(actualValidators, len(expectedValidators))
+		for nodeID, expectedValidator := range expectedValidators {
+			actualValidator, ok := actualValidators[nodeID]
+			require.True(ok)
+			require.Equal(expectedValidator.validator, actualValidator.validator)
+			assertIteratorsEqual(
+				t,
+				iterator.FromTree(expectedValidator.delegators),
+				iterator.FromTree(actualValidator.delegators),
+			)
+		}
+	}
+
+	require.Len(actual.validatorDiffs, len(expected.validatorDiffs))
+	for subnetID, expectedDiffs := range expected.validatorDiffs {
+		actualDiffs := actual.validatorDiffs[subnetID]
+		require.Len(actualDiffs, len(expectedDiffs))
+		for nodeID, expectedDiff := range expectedDiffs {
+			actualDiff, ok := actualDiffs[nodeID]
+			require.True(ok)
+			require.Equal(expectedDiff.validatorStatus, actualDiff.validatorStatus)
+			require.Equal(expectedDiff.validator, actualDiff.validator)
+			require.Equal(expectedDiff.deletedDelegators, actualDiff.deletedDelegators)
+			assertIteratorsEqual(
+				t,
+				iterator.FromTree(expectedDiff.addedDelegators),
+				iterator.FromTree(actualDiff.addedDelegators),
+			)
+		}
 	}
 }
 
@@ -8,10 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sync"
 	"time"
 
-	"github.com/google/btree"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
@@ -27,6 +27,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/upgrade"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/hashing"
@@ -294,8 +295,10 @@ type state struct {
 
 	baseDB *versiondb.Database
 
-	currentStakers *baseStakers
-	pendingStakers *baseStakers
+	currentStakers       *baseStakers
+	pendingStakers       *baseStakers
+	currentStakerMetrics *stakerMetrics
+	pendingStakerMetrics *stakerMetrics
 
 	currentHeight uint64
 
@@ -586,6 +589,15 @@ func New(
 		return nil, err
 	}
 
+	currentStakerMetrics, err := newStakerMetrics(metricsReg, "stakers", "current")
+	if err != nil {
+		return nil, err
+	}
+	pendingStakerMetrics, err := newStakerMetrics(metricsReg, "pending_stakers", "pending")
+	if err != nil {
+		return nil, err
+	}
+
 	s := &state{
 		validatorState: newValidatorState(),
 
@@ -604,8 +616,10 @@ func New(
 		blockCache:  blockCache,
 		blockDB:     prefixdb.New(BlockPrefix, baseDB),
 
-		currentStakers: newBaseStakers(),
-		pendingStakers: newBaseStakers(),
+		currentStakers:       newMeteredBaseStakers(currentStakerMetrics),
+		pendingStakers:       newMeteredBaseStakers(pendingStakerMetrics),
+		currentStakerMetrics: currentStakerMetrics,
+		pendingStakerMetrics: pendingStakerMetrics,
 
 		validatorsDB:                 validatorsDB,
 		currentValidatorsDB:          currentValidatorsDB,
@@ -695,6 +709,10 @@ func (s *state) GetCurrentDelegatorIterator(subnetID ids.ID, nodeID ids.NodeID)
 	return s.currentStakers.GetDelegatorIterator(subnetID, nodeID), nil
 }
 
+func (s *state) NumDelegators(subnetID ids.ID, nodeID ids.NodeID) int {
+	return s.currentStakers.NumDelegators(subnetID, nodeID)
+}
+
 func (s *state) PutCurrentDelegator(staker *Staker) {
 	s.currentStakers.PutDelegator(staker)
 }
@@ -707,6 +725,13 @@ func (s *state) GetCurrentStakerIterator() (iterator.Iterator[*Staker], error) {
 	return s.currentStakers.GetStakerIterator(), nil
 }
 
+// SnapshotCurrentStakers returns an immutable view of the current stakers that
+// can be read without holding the lock that guards the state, such as while
+// backing up the stakers as the chain advances.
+func (s *state) SnapshotCurrentStakers() *stakersSnapshot {
+	return s.currentStakers.Snapshot()
+}
+
 func (s *state) GetPendingValidator(subnetID ids.ID, nodeID ids.NodeID) (*Staker, error) {
 	return s.pendingStakers.GetValidator(subnetID, nodeID)
 }
@@ -736,6 +761,141 @@ func (s *state) GetPendingStakerIterator() (iterator.Iterator[*Staker], error) {
 	return s.pendingStakers.GetStakerIterator(), nil
 }
 
+// PendingSubnetsForNode returns the sorted IDs of the subnets on which [nodeID]
+// has a pending validator but not a current validator.
+func (s *state) PendingSubnetsForNode(nodeID ids.NodeID) []ids.ID {
+	var subnetIDs []ids.ID
+	for subnetID, subnetValidators := range s.pendingStakers.validators {
+		validator, ok := subnetValidators[nodeID]
+		if !ok || validator.validator == nil {
+			continue
+		}
+		if _, err := s.currentStakers.GetValidator(subnetID, nodeID); err == nil {
+			continue
+		}
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+	utils.Sort(subnetIDs)
+	return subnetIDs
+}
+
+// ProjectedValidatorCount returns the number of current and pending validators
+// on [subnetID] that will be active at [at], assuming no further changes are
+// made to the staker sets.
+func (s *state) ProjectedValidatorCount(subnetID ids.ID, at time.Time) int {
+	return s.currentStakers.numValidatorsActiveAt(subnetID, at) +
+		s.pendingStakers.numValidatorsActiveAt(subnetID, at)
+}
+
+// ProjectedDelta returns the pending validators on [subnetID] that will start
+// validating and the current validators on [subnetID] that will stop
+// validating in (from, to], assuming no further changes are made to the staker
+// sets. Both are sorted in order of their removal from their staker set.
+func (s *state) ProjectedDelta(subnetID ids.ID, from, to time.Time) ([]*Staker, []*Staker) {
+	inWindow := func(t time.Time) bool {
+		return t.After(from) && !t.After(to)
+	}
+
+	var joining []*Staker
+	for _, validator := range s.pendingStakers.subnetValidators(subnetID) {
+		if inWindow(validator.StartTime) {
+			joining = append(joining, validator)
+		}
+	}
+	slices.SortFunc(joining, compareStakers)
+
+	var leaving []*Staker
+	for _, validator := range s.currentStakers.subnetValidators(subnetID) {
+		if inWindow(validator.EndTime) {
+			leaving = append(leaving, validator)
+		}
+	}
+	slices.SortFunc(leaving, compareStakers)
+	return joining, leaving
+}
+
+// NetStakeFlow returns the weight of the pending validators on [subnetID] that
+// start before [until] minus the weight of the current validators on
+// [subnetID] that end before [until].
+func (s *state) NetStakeFlow(subnetID ids.ID, until time.Time) (int64, error) {
+	var joining []*Staker
+	for _, validator := range s.pendingStakers.subnetValidators(subnetID) {
+		if validator.StartTime.Before(until) {
+			joining = append(joining, validator)
+		}
+	}
+	joiningWeight, err := sumWeights(stakerWeights(joining))
+	if err != nil {
+		return 0, err
+	}
+
+	var leaving []*Staker
+	for _, validator := range s.currentStakers.subnetValidators(subnetID) {
+		if validator.EndTime.Before(until) {
+			leaving = append(leaving, validator)
+		}
+	}
+	leavingWeight, err := sumWeights(stakerWeights(leaving))
+	if err != nil {
+		return 0, err
+	}
+
+	if joiningWeight > math.MaxInt64 || leavingWeight > math.MaxInt64 {
+		return 0, safemath.ErrOverflow
+	}
+	return int64(joiningWeight) - int64(leavingWeight), nil
+}
+
+// WeightedGeometricMeanUptime returns the geometric mean of the uptimes of the
+// current validators on [subnetID], weighted by their stake. A validator's
+// uptime is the fraction of the time between its start and its last uptime
+// update that it was online. If any validator with non-zero weight has a zero
+// uptime, the mean is zero.
+func (s *state) WeightedGeometricMeanUptime(subnetID ids.ID) (float64, error) {
+	validators := s.currentStakers.subnetValidators(subnetID)
+	if len(validators) == 0 {
+		return 0, errNoValidators
+	}
+
+	var (
+		totalWeight    float64
+		weightedLogSum float64
+		hasZeroUptime  bool
+	)
+	for _, validator := range validators {
+		if validator.Weight == 0 {
+			continue
+		}
+
+		upDuration, lastUpdated, err := s.GetUptime(validator.NodeID, subnetID)
+		if err != nil {
+			return 0, err
+		}
+
+		// As in the uptime manager, a validator that hasn't had any time to be
+		// online is considered to be fully online.
+		uptime := 1.
+		if bestPossibleUpDuration := lastUpdated.Sub(validator.StartTime); bestPossibleUpDuration > 0 {
+			uptime = min(float64(upDuration)/float64(bestPossibleUpDuration), 1)
+		}
+
+		weight := float64(validator.Weight)
+		totalWeight += weight
+		if uptime == 0 {
+			hasZeroUptime = true
+			continue
+		}
+		weightedLogSum += weight * math.Log(uptime)
+	}
+	if totalWeight == 0 {
+		return 0, errZeroWeight
+	}
+	if hasZeroUptime {
+		return 0, nil
+	}
+	return math.Exp(weightedLogSum / totalWeight), nil
+}
+
 func (s *state) GetSubnetIDs() ([]ids.ID, error) {
 	if s.cachedSubnetIDs != nil {
 		return s.cachedSubnetIDs, nil
@@ -1042,6 +1202,8 @@ func (s *state) GetTimestamp() time.Time {
 
 func (s *state) SetTimestamp(tm time.Time) {
 	s.timestamp = tm
+	s.currentStakers.SetTimestamp(tm)
+	s.pendingStakers.SetTimestamp(tm)
 }
 
 func (s *state) GetFeeState() gas.State {
@@ -1387,7 +1549,8 @@ func (s *state) loadMetadata() error {
 }
 
 func (s *state) loadCurrentValidators() error {
-	s.currentStakers = newBaseStakers()
+	s.currentStakers = newMeteredBaseStakers(s.currentStakerMetrics)
+	s.currentStakers.SetTimestamp(s.timestamp)
 
 	validatorIt := s.currentValidatorList.NewIterator()
 	defer validatorIt.Release()
@@ -1432,10 +1595,7 @@ func (s *state) loadCurrentValidators() error {
 			return err
 		}
 
-		validator := s.currentStakers.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-		validator.validator = staker
-
-		s.currentStakers.stakers.ReplaceOrInsert(staker)
+		s.currentStakers.loadValidator(staker)
 
 		s.validatorState.LoadValidatorMetadata(staker.NodeID, staker.SubnetID, metadata)
 	}
@@ -1482,10 +1642,7 @@ func (s *state) loadCurrentValidators() error {
 		if err != nil {
 			return err
 		}
-		validator := s.currentStakers.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-		validator.validator = staker
-
-		s.currentStakers.stakers.ReplaceOrInsert(staker)
+		s.currentStakers.loadValidator(staker)
 
 		s.validatorState.LoadValidatorMetadata(staker.NodeID, staker.SubnetID, metadata)
 	}
@@ -1538,13 +1695,7 @@ func (s *state) loadCurrentValidators() error {
 				return err
 			}
 
-			validator := s.currentStakers.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-			if validator.delegators == nil {
-				validator.delegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
-			}
-			validator.delegators.ReplaceOrInsert(staker)
-
-			s.currentStakers.stakers.ReplaceOrInsert(staker)
+			s.currentStakers.loadDelegator(staker)
 		}
 	}
 
@@ -1557,7 +1708,8 @@ func (s *state) loadCurrentValidators() error {
 }
 
 func (s *state) loadPendingValidators() error {
-	s.pendingStakers = newBaseStakers()
+	s.pendingStakers = newMeteredBaseStakers(s.pendingStakerMetrics)
+	s.pendingStakers.SetTimestamp(s.timestamp)
 
 	validatorIt := s.pendingValidatorList.NewIterator()
 	defer validatorIt.Release()
@@ -1587,10 +1739,7 @@ func (s *state) loadPendingValidators() error {
 				return err
 			}
 
-			validator := s.pendingStakers.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-			validator.validator = staker
-
-			s.pendingStakers.stakers.ReplaceOrInsert(staker)
+			s.pendingStakers.loadValidator(staker)
 		}
 	}
 
@@ -1622,13 +1771,7 @@ func (s *state) loadPendingValidators() error {
 				return err
 			}
 
-			validator := s.pendingStakers.getOrCreateValidator(staker.SubnetID, staker.NodeID)
-			if validator.delegators == nil {
-				validator.delegators = btree.NewG(defaultTreeDegree, (*Staker).Less)
-			}
-			validator.delegators.ReplaceOrInsert(staker)
-
-			s.pendingStakers.stakers.ReplaceOrInsert(staker)
+			s.pendingStakers.loadDelegator(staker)
 		}
 	}
 
@@ -1915,7 +2058,7 @@ func (s *state) writeCurrentStakers(updateValidators bool, height uint64, codecV
 		// Select db to write to
 		validatorDB := s.currentSubnetValidatorList
 		delegatorDB := s.currentSubnetDelegatorList
-		if subnetID == constants.PrimaryNetworkID {
+		if s.currentStakers.IsPrimaryNetwork(subnetID) {
 			validatorDB = s.currentValidatorList
 			delegatorDB = s.currentDelegatorList
 		}
@@ -2114,7 +2257,7 @@ func (s *state) writePendingStakers() error {
 
 		validatorDB := s.pendingSubnetValidatorList
 		delegatorDB := s.pendingSubnetDelegatorList
-		if subnetID == constants.PrimaryNetworkID {
+		if s.pendingStakers.IsPrimaryNetwork(subnetID) {
 			validatorDB = s.pendingValidatorList
 			delegatorDB = s.pendingDelegatorList
 		}
@@ -24,6 +24,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/upgrade/upgradetest"
+	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/iterator"
@@ -50,6 +51,14 @@ import (
 var defaultValidatorNodeID = ids.GenerateTestNodeID()
 
 func newTestState(t testing.TB, db database.Database) *state {
+	rewards, err := reward.NewCalculator(reward.Config{
+		MaxConsumptionRate: .12 * reward.PercentDenominator,
+		MinConsumptionRate: .1 * reward.PercentDenominator,
+		MintingPeriod:      365 * 24 * time.Hour,
+		SupplyCap:          720 * units.MegaAvax,
+	})
+	require.NoError(t, err)
+
 	s, err := New(
 		db,
 		genesistest.NewBytes(t, genesistest.Config{
@@ -65,12 +74,7 @@ func newTestState(t testing.TB, db database.Database) *state {
 			Log:       logging.NoLog{},
 		},
 		metrics.Noop,
-		reward.NewCalculator(reward.Config{
-			MaxConsumptionRate: .12 * reward.PercentDenominator,
-			MinConsumptionRate: .1 * reward.PercentDenominator,
-			MintingPeriod:      365 * 24 * time.Hour,
-			SupplyCap:          720 * units.MegaAvax,
-		}),
+		rewards,
 	)
 	require.NoError(t, err)
 	require.IsType(t, (*state)(nil), s)
@@ -836,6 +840,305 @@ func createPermissionlessDelegatorTx(subnetID ids.ID, delegatorData txs.Validato
 	}
 }
 
+func TestStatePendingSubnetsForNode(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	// The genesis validator is only current on the primary network.
+	require.Empty(state.PendingSubnetsForNode(defaultValidatorNodeID))
+
+	var (
+		currentSubnetID  = ids.GenerateTestID()
+		pendingSubnetIDs = []ids.ID{
+			ids.GenerateTestID(),
+			ids.GenerateTestID(),
+		}
+	)
+	utils.Sort(pendingSubnetIDs)
+
+	currentValidator := newTestStaker()
+	currentValidator.NodeID = defaultValidatorNodeID
+	currentValidator.SubnetID = currentSubnetID
+	currentValidator.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+	require.NoError(state.PutCurrentValidator(currentValidator))
+
+	for _, subnetID := range pendingSubnetIDs {
+		pendingValidator := newTestStaker()
+		pendingValidator.NodeID = defaultValidatorNodeID
+		pendingValidator.SubnetID = subnetID
+		pendingValidator.Priority = txs.SubnetPermissionedValidatorPendingPriority
+		require.NoError(state.PutPendingValidator(pendingValidator))
+	}
+
+	// Pending validators of other nodes must be ignored.
+	otherValidator := newTestStaker()
+	otherValidator.Priority = txs.SubnetPermissionedValidatorPendingPriority
+	require.NoError(state.PutPendingValidator(otherValidator))
+
+	require.Equal(pendingSubnetIDs, state.PendingSubnetsForNode(defaultValidatorNodeID))
+}
+
+func TestStateIdleSubnets(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	var (
+		subnetID = ids.GenerateTestID()
+		addedAt  = time.Unix(1000, 0)
+		now      = time.Unix(2000, 0)
+	)
+
+	newValidator := func(priority txs.Priority) *Staker {
+		validator := newTestStaker()
+		validator.SubnetID = subnetID
+		validator.StartTime = now.Add(time.Hour)
+		validator.EndTime = now.Add(24 * time.Hour)
+		validator.Priority = priority
+		return validator
+	}
+
+	state.SetTimestamp(addedAt)
+	current := newValidator(txs.SubnetPermissionedValidatorCurrentPriority)
+	require.NoError(state.PutCurrentValidator(current))
+	pending := newValidator(txs.SubnetPermissionedValidatorPendingPriority)
+	require.NoError(state.PutPendingValidator(pending))
+	removed := newValidator(txs.SubnetPermissionedValidatorPendingPriority)
+	require.NoError(state.PutPendingValidator(removed))
+
+	require.Equal([]ids.ID{subnetID}, state.pendingStakers.IdleSubnets(now))
+
+	// Removing a pending staker before its EndTime must be recorded at the
+	// chain time of the removal.
+	state.SetTimestamp(now)
+	state.DeletePendingValidator(removed)
+	require.Empty(state.pendingStakers.IdleSubnets(now))
+
+	// Promoting a pending staker must be recorded on the current stakers at
+	// the chain time of the promotion.
+	require.Equal([]ids.ID{subnetID}, state.currentStakers.IdleSubnets(now))
+	state.DeletePendingValidator(pending)
+	promoted := *pending
+	promoted.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+	require.NoError(state.PutCurrentValidator(&promoted))
+	require.Empty(state.currentStakers.IdleSubnets(now))
+	require.Equal([]ids.ID{subnetID}, state.currentStakers.IdleSubnets(now.Add(time.Second)))
+}
+
+func TestStateProjectedValidatorCount(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+
+	// Active now and expiring after an hour.
+	expiring := newTestStaker()
+	expiring.SubnetID = subnetID
+	expiring.StartTime = now.Add(-time.Hour)
+	expiring.EndTime = now.Add(time.Hour)
+	expiring.NextTime = expiring.EndTime
+	expiring.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+	require.NoError(state.PutCurrentValidator(expiring))
+
+	// Active now and for the next day.
+	longLived := newTestStaker()
+	longLived.SubnetID = subnetID
+	longLived.StartTime = now.Add(-time.Hour)
+	longLived.EndTime = now.Add(24 * time.Hour)
+	longLived.NextTime = longLived.EndTime
+	longLived.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+	require.NoError(state.PutCurrentValidator(longLived))
+
+	// Activating after two hours.
+	pending := newTestStaker()
+	pending.SubnetID = subnetID
+	pending.StartTime = now.Add(2 * time.Hour)
+	pending.EndTime = now.Add(48 * time.Hour)
+	pending.NextTime = pending.StartTime
+	pending.Priority = txs.SubnetPermissionedValidatorPendingPriority
+	require.NoError(state.PutPendingValidator(pending))
+
+	require.Equal(2, state.ProjectedValidatorCount(subnetID, now))
+	require.Equal(1, state.ProjectedValidatorCount(subnetID, now.Add(time.Hour)))
+	require.Equal(2, state.ProjectedValidatorCount(subnetID, now.Add(2*time.Hour)))
+	require.Equal(1, state.ProjectedValidatorCount(subnetID, now.Add(24*time.Hour)))
+	require.Zero(state.ProjectedValidatorCount(subnetID, now.Add(48*time.Hour)))
+}
+
+func TestStateProjectedDelta(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+
+	newValidator := func(startTime, endTime time.Time, priority txs.Priority) *Staker {
+		validator := newTestStaker()
+		validator.SubnetID = subnetID
+		validator.StartTime = startTime
+		validator.EndTime = endTime
+		validator.Priority = priority
+		if priority.IsPending() {
+			validator.NextTime = startTime
+		} else {
+			validator.NextTime = endTime
+		}
+		return validator
+	}
+
+	// Expiring within the window.
+	expiring := newValidator(now.Add(-time.Hour), now.Add(time.Hour), txs.SubnetPermissionedValidatorCurrentPriority)
+	require.NoError(state.PutCurrentValidator(expiring))
+
+	// Expiring after the window.
+	longLived := newValidator(now.Add(-time.Hour), now.Add(24*time.Hour), txs.SubnetPermissionedValidatorCurrentPriority)
+	require.NoError(state.PutCurrentValidator(longLived))
+
+	// Activating within the window.
+	activating := newValidator(now.Add(2*time.Hour), now.Add(48*time.Hour), txs.SubnetPermissionedValidatorPendingPriority)
+	require.NoError(state.PutPendingValidator(activating))
+
+	// Activating after the window.
+	late := newValidator(now.Add(12*time.Hour), now.Add(48*time.Hour), txs.SubnetPermissionedValidatorPendingPriority)
+	require.NoError(state.PutPendingValidator(late))
+
+	joining, leaving := state.ProjectedDelta(subnetID, now, now.Add(2*time.Hour))
+	require.Equal([]*Staker{activating}, joining)
+	require.Equal([]*Staker{expiring}, leaving)
+
+	joining, leaving = state.ProjectedDelta(subnetID, now.Add(2*time.Hour), now.Add(24*time.Hour))
+	require.Equal([]*Staker{late}, joining)
+	require.Equal([]*Staker{longLived}, leaving)
+
+	joining, leaving = state.ProjectedDelta(subnetID, now.Add(48*time.Hour), now.Add(96*time.Hour))
+	require.Empty(joining)
+	require.Empty(leaving)
+}
+
+func TestStateNetStakeFlow(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	var (
+		subnetID = ids.GenerateTestID()
+		now      = time.Unix(1000, 0)
+	)
+
+	flow, err := state.NetStakeFlow(subnetID, now.Add(time.Hour))
+	require.NoError(err)
+	require.Zero(flow)
+
+	newValidator := func(weight uint64, startTime, endTime time.Time, priority txs.Priority) *Staker {
+		validator := newTestStaker()
+		validator.SubnetID = subnetID
+		validator.Weight = weight
+		validator.StartTime = startTime
+		validator.EndTime = endTime
+		validator.Priority = priority
+		if priority.IsPending() {
+			validator.NextTime = startTime
+		} else {
+			validator.NextTime = endTime
+		}
+		return validator
+	}
+
+	// Expiring after 1 hour.
+	require.NoError(state.PutCurrentValidator(newValidator(
+		100,
+		now.Add(-time.Hour),
+		now.Add(time.Hour),
+		txs.SubnetPermissionedValidatorCurrentPriority,
+	)))
+	// Expiring after 1 day.
+	require.NoError(state.PutCurrentValidator(newValidator(
+		1000,
+		now.Add(-time.Hour),
+		now.Add(24*time.Hour),
+		txs.SubnetPermissionedValidatorCurrentPriority,
+	)))
+	// Activating after 2 hours.
+	require.NoError(state.PutPendingValidator(newValidator(
+		30,
+		now.Add(2*time.Hour),
+		now.Add(48*time.Hour),
+		txs.SubnetPermissionedValidatorPendingPriority,
+	)))
+
+	tests := []struct {
+		until    time.Time
+		expected int64
+	}{
+		{
+			until:    now.Add(time.Hour),
+			expected: 0,
+		},
+		{
+			until:    now.Add(2 * time.Hour),
+			expected: -100,
+		},
+		{
+			until:    now.Add(3 * time.Hour),
+			expected: 30 - 100,
+		},
+		{
+			until:    now.Add(25 * time.Hour),
+			expected: 30 - 100 - 1000,
+		},
+	}
+	for _, test := range tests {
+		flow, err := state.NetStakeFlow(subnetID, test.until)
+		require.NoError(err)
+		require.Equal(test.expected, flow)
+	}
+}
+
+func TestStateWeightedGeometricMeanUptime(t *testing.T) {
+	require := require.New(t)
+	state := newTestState(t, memdb.New())
+
+	subnetID := ids.GenerateTestID()
+
+	_, err := state.WeightedGeometricMeanUptime(subnetID)
+	require.ErrorIs(err, errNoValidators)
+
+	var (
+		startTime   = time.Unix(0, 0)
+		lastUpdated = time.Unix(100, 0)
+	)
+	putValidator := func(weight uint64, upDuration time.Duration) {
+		validator := newTestStaker()
+		validator.SubnetID = subnetID
+		validator.Weight = weight
+		validator.StartTime = startTime
+		validator.Priority = txs.SubnetPermissionedValidatorCurrentPriority
+		require.NoError(state.PutCurrentValidator(validator))
+		state.LoadValidatorMetadata(validator.NodeID, subnetID, &validatorMetadata{
+			UpDuration:  upDuration,
+			lastUpdated: lastUpdated,
+		})
+	}
+
+	putValidator(1, 50*time.Second)
+	putValidator(3, 100*time.Second)
+
+	// (0.5^1 * 1^3)^(1/4)
+	mean, err := state.WeightedGeometricMeanUptime(subnetID)
+	require.NoError(err)
+	require.InDelta(0.8408964, mean, floatDelta)
+
+	// A single validator that was never online zeroes the mean.
+	putValidator(1, 0)
+
+	mean, err = state.WeightedGeometricMeanUptime(subnetID)
+	require.NoError(err)
+	require.Zero(mean)
+}
+
 func TestValidatorWeightDiff(t *testing.T) {
 	type test struct {
 		name        string
@@ -71,12 +71,14 @@ func New(t testing.TB, c Config) state.State {
 		c.Metrics = metrics.Noop
 	}
 	if c.Rewards == nil {
-		c.Rewards = reward.NewCalculator(reward.Config{
+		rewards, err := reward.NewCalculator(reward.Config{
 			MaxConsumptionRate: .12 * reward.PercentDenominator,
 			MinConsumptionRate: .1 * reward.PercentDenominator,
 			MintingPeriod:      365 * 24 * time.Hour,
 			SupplyCap:          720 * units.MegaAvax,
 		})
+		require.NoError(t, err)
+		c.Rewards = rewards
 	}
 
 	s, err := state.New(
@@ -0,0 +1,186 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	_ Visitor = (*AmountsVisitor)(nil)
+
+	errOutputsExceedInputs = errors.New("outputs exceed inputs")
+)
+
+// Amounts describes how the value consumed by a transaction is distributed.
+// For valid transactions, Staked + Burned + Transferred equals the amount
+// consumed by the transaction's inputs.
+type Amounts struct {
+	// Staked is the amount locked in the stake outputs of the transaction.
+	Staked uint64 `json:"staked"`
+	// Burned is the amount consumed by the transaction without being returned
+	// in any output.
+	Burned uint64 `json:"burned"`
+	// Transferred is the amount returned in the transaction's outputs,
+	// including change and outputs exported to other chains.
+	Transferred uint64 `json:"transferred"`
+}
+
+// AmountsOf returns the [Amounts] of [assetID] staked, burned, and transferred
+// by [tx].
+func AmountsOf(tx UnsignedTx, assetID ids.ID) (Amounts, error) {
+	v := AmountsVisitor{
+		AssetID: assetID,
+	}
+	err := tx.Visit(&v)
+	return v.Amounts, err
+}
+
+// AmountsVisitor calculates the [Amounts] of a single asset that are staked,
+// burned, and transferred by the visited transaction. Inputs and outputs of
+// other assets are ignored.
+type AmountsVisitor struct {
+	// inputs
+	AssetID ids.ID
+
+	// outputs
+	Amounts Amounts
+}
+
+func (*AmountsVisitor) AdvanceTimeTx(*AdvanceTimeTx) error {
+	return nil
+}
+
+func (*AmountsVisitor) RewardValidatorTx(*RewardValidatorTx) error {
+	return nil
+}
+
+func (v *AmountsVisitor) AddValidatorTx(tx *AddValidatorTx) error {
+	return v.visit(tx.Ins, tx.Outs, tx.StakeOuts)
+}
+
+func (v *AmountsVisitor) AddSubnetValidatorTx(tx *AddSubnetValidatorTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) AddDelegatorTx(tx *AddDelegatorTx) error {
+	return v.visit(tx.Ins, tx.Outs, tx.StakeOuts)
+}
+
+func (v *AmountsVisitor) CreateChainTx(tx *CreateChainTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) CreateSubnetTx(tx *CreateSubnetTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) ImportTx(tx *ImportTx) error {
+	ins := make([]*avax.TransferableInput, 0, len(tx.Ins)+len(tx.ImportedInputs))
+	ins = append(ins, tx.Ins...)
+	ins = append(ins, tx.ImportedInputs...)
+	return v.visit(ins, tx.Outs, nil)
+}
+
+func (v *AmountsVisitor) ExportTx(tx *ExportTx) error {
+	outs := make([]*avax.TransferableOutput, 0, len(tx.Outs)+len(tx.ExportedOutputs))
+	outs = append(outs, tx.Outs...)
+	outs = append(outs, tx.ExportedOutputs...)
+	return v.visit(tx.Ins, outs, nil)
+}
+
+func (v *AmountsVisitor) RemoveSubnetValidatorTx(tx *RemoveSubnetValidatorTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) TransformSubnetTx(tx *TransformSubnetTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) AddPermissionlessValidatorTx(tx *AddPermissionlessValidatorTx) error {
+	return v.visit(tx.Ins, tx.Outs, tx.StakeOuts)
+}
+
+func (v *AmountsVisitor) AddPermissionlessDelegatorTx(tx *AddPermissionlessDelegatorTx) error {
+	return v.visit(tx.Ins, tx.Outs, tx.StakeOuts)
+}
+
+func (v *AmountsVisitor) TransferSubnetOwnershipTx(tx *TransferSubnetOwnershipTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) ConvertSubnetTx(tx *ConvertSubnetTx) error {
+	return v.BaseTx(&tx.BaseTx)
+}
+
+func (v *AmountsVisitor) BaseTx(tx *BaseTx) error {
+	return v.visit(tx.Ins, tx.Outs, nil)
+}
+
+func (v *AmountsVisitor) visit(
+	ins []*avax.TransferableInput,
+	outs []*avax.TransferableOutput,
+	stakeOuts []*avax.TransferableOutput,
+) error {
+	var consumed uint64
+	for _, in := range ins {
+		if in.AssetID() != v.AssetID {
+			continue
+		}
+		var err error
+		consumed, err = math.Add(consumed, in.In.Amount())
+		if err != nil {
+			return err
+		}
+	}
+
+	transferred, err := v.sumOutputs(outs)
+	if err != nil {
+		return err
+	}
+	staked, err := v.sumOutputs(stakeOuts)
+	if err != nil {
+		return err
+	}
+
+	produced, err := math.Add(transferred, staked)
+	if err != nil {
+		return err
+	}
+	burned, err := math.Sub(consumed, produced)
+	if err != nil {
+		return fmt.Errorf("%w: consumed %d but produced %d",
+			errOutputsExceedInputs,
+			consumed,
+			produced,
+		)
+	}
+
+	v.Amounts = Amounts{
+		Staked:      staked,
+		Burned:      burned,
+		Transferred: transferred,
+	}
+	return nil
+}
+
+func (v *AmountsVisitor) sumOutputs(outs []*avax.TransferableOutput) (uint64, error) {
+	var total uint64
+	for _, out := range outs {
+		if out.AssetID() != v.AssetID {
+			continue
+		}
+		var err error
+		total, err = math.Add(total, out.Out.Amount())
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/stakeable"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	safemath "github.com/ava-labs/avalanchego/utils/math"
+)
+
+func TestAmountsOf(t *testing.T) {
+	var (
+		avaxAssetID  = ids.GenerateTestID()
+		otherAssetID = ids.GenerateTestID()
+		owner        = secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{ids.GenerateTestShortID()},
+		}
+	)
+
+	newIn := func(assetID ids.ID, amount uint64) *avax.TransferableInput {
+		return &avax.TransferableInput{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			In: &secp256k1fx.TransferInput{
+				Amt:   amount,
+				Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+			},
+		}
+	}
+	newOut := func(assetID ids.ID, amount uint64) *avax.TransferableOutput {
+		return &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amount,
+				OutputOwners: owner,
+			},
+		}
+	}
+	newLockedOut := func(assetID ids.ID, amount uint64) *avax.TransferableOutput {
+		return &avax.TransferableOutput{
+			Asset: avax.Asset{ID: assetID},
+			Out: &stakeable.LockOut{
+				Locktime: 100,
+				TransferableOut: &secp256k1fx.TransferOutput{
+					Amt:          amount,
+					OutputOwners: owner,
+				},
+			},
+		}
+	}
+	newBaseTx := func(ins []*avax.TransferableInput, outs []*avax.TransferableOutput) BaseTx {
+		return BaseTx{BaseTx: avax.BaseTx{
+			Ins:  ins,
+			Outs: outs,
+		}}
+	}
+
+	tests := []struct {
+		name            string
+		tx              UnsignedTx
+		expectedAmounts Amounts
+		expectedErr     error
+	}{
+		{
+			name: "BaseTx",
+			tx: &BaseTx{BaseTx: avax.BaseTx{
+				Ins: []*avax.TransferableInput{
+					newIn(avaxAssetID, 1000),
+					newIn(otherAssetID, 5000), // ignored
+				},
+				Outs: []*avax.TransferableOutput{
+					newOut(avaxAssetID, 400),
+					newOut(avaxAssetID, 500),
+					newOut(otherAssetID, 5000), // ignored
+				},
+			}},
+			expectedAmounts: Amounts{
+				Burned:      100,
+				Transferred: 900,
+			},
+		},
+		{
+			name: "AddValidatorTx",
+			tx: &AddValidatorTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 3000),
+					},
+					[]*avax.TransferableOutput{
+						newOut(avaxAssetID, 900), // change
+					},
+				),
+				StakeOuts: []*avax.TransferableOutput{
+					newOut(avaxAssetID, 1500),
+					newLockedOut(avaxAssetID, 500),
+				},
+				RewardsOwner: &owner,
+			},
+			expectedAmounts: Amounts{
+				Staked:      2000,
+				Burned:      100,
+				Transferred: 900,
+			},
+		},
+		{
+			name: "AddDelegatorTx",
+			tx: &AddDelegatorTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 2000),
+					},
+					nil,
+				),
+				StakeOuts: []*avax.TransferableOutput{
+					newLockedOut(avaxAssetID, 1990),
+				},
+				DelegationRewardsOwner: &owner,
+			},
+			expectedAmounts: Amounts{
+				Staked: 1990,
+				Burned: 10,
+			},
+		},
+		{
+			name: "AddPermissionlessValidatorTx",
+			tx: &AddPermissionlessValidatorTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 100),
+						newIn(otherAssetID, 2000),
+					},
+					[]*avax.TransferableOutput{
+						newOut(otherAssetID, 1000),
+					},
+				),
+				StakeOuts: []*avax.TransferableOutput{
+					newOut(otherAssetID, 1000), // ignored
+				},
+			},
+			expectedAmounts: Amounts{
+				Burned: 100,
+			},
+		},
+		{
+			name: "AddPermissionlessDelegatorTx",
+			tx: &AddPermissionlessDelegatorTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 5000),
+					},
+					[]*avax.TransferableOutput{
+						newOut(avaxAssetID, 2000),
+					},
+				),
+				StakeOuts: []*avax.TransferableOutput{
+					newOut(avaxAssetID, 3000),
+				},
+			},
+			expectedAmounts: Amounts{
+				Staked:      3000,
+				Transferred: 2000,
+			},
+		},
+		{
+			name: "ImportTx",
+			tx: &ImportTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 100),
+					},
+					[]*avax.TransferableOutput{
+						newOut(avaxAssetID, 1050),
+					},
+				),
+				ImportedInputs: []*avax.TransferableInput{
+					newIn(avaxAssetID, 1000),
+				},
+			},
+			expectedAmounts: Amounts{
+				Burned:      50,
+				Transferred: 1050,
+			},
+		},
+		{
+			name: "ExportTx",
+			tx: &ExportTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 1000),
+					},
+					[]*avax.TransferableOutput{
+						newOut(avaxAssetID, 300), // change
+					},
+				),
+				ExportedOutputs: []*avax.TransferableOutput{
+					newOut(avaxAssetID, 600),
+				},
+			},
+			expectedAmounts: Amounts{
+				Burned:      100,
+				Transferred: 900,
+			},
+		},
+		{
+			name: "CreateSubnetTx",
+			tx: &CreateSubnetTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 1000),
+					},
+					nil,
+				),
+				Owner: &owner,
+			},
+			expectedAmounts: Amounts{
+				Burned: 1000,
+			},
+		},
+		{
+			name:            "RewardValidatorTx",
+			tx:              &RewardValidatorTx{TxID: ids.GenerateTestID()},
+			expectedAmounts: Amounts{},
+		},
+		{
+			name: "outputs exceed inputs",
+			tx: &AddValidatorTx{
+				BaseTx: newBaseTx(
+					[]*avax.TransferableInput{
+						newIn(avaxAssetID, 1000),
+					},
+					[]*avax.TransferableOutput{
+						newOut(avaxAssetID, 500),
+					},
+				),
+				StakeOuts: []*avax.TransferableOutput{
+					newOut(avaxAssetID, 1000),
+				},
+			},
+			expectedErr: errOutputsExceedInputs,
+		},
+		{
+			name: "input overflow",
+			tx: &BaseTx{BaseTx: avax.BaseTx{
+				Ins: []*avax.TransferableInput{
+					newIn(avaxAssetID, math.MaxUint64),
+					newIn(avaxAssetID, 1),
+				},
+			}},
+			expectedErr: safemath.ErrOverflow,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			amounts, err := AmountsOf(test.tx, avaxAssetID)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.Equal(test.expectedAmounts, amounts)
+		})
+	}
+}
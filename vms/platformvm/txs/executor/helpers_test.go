@@ -106,7 +106,8 @@ func newEnvironment(t *testing.T, f upgradetest.Fork) *environment {
 
 	fx := defaultFx(clk, ctx.Log, isBootstrapped.Get())
 
-	rewards := reward.NewCalculator(config.RewardConfig)
+	rewards, err := reward.NewCalculator(config.RewardConfig)
+	require.NoError(t, err)
 	baseState := statetest.New(t, statetest.Config{
 		DB:         baseDB,
 		Genesis:    genesistest.NewBytes(t, genesistest.Config{}),
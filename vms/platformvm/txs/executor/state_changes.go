@@ -206,5 +206,5 @@ func GetRewardsCalculator(
 		MinConsumptionRate: transformSubnet.MinConsumptionRate,
 		MintingPeriod:      backend.Config.RewardConfig.MintingPeriod,
 		SupplyCap:          transformSubnet.MaximumSupply,
-	}), nil
+	})
 }
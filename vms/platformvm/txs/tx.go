@@ -25,6 +25,7 @@ var (
 	ErrNilSignedTx = errors.New("nil signed tx is not valid")
 
 	errSignedTxNotInitialized = errors.New("signed tx was never initialized and is not valid")
+	errNegativeNumSigs        = errors.New("negative number of signatures")
 )
 
 // Tx is a signed transaction
@@ -48,6 +49,28 @@ func NewSigned(
 	return res, res.Sign(c, signers)
 }
 
+// EstimateSize returns the size of [utx] once it is signed with [numSigs]
+// secp256k1 signatures. Each signature is assumed to be in its own credential,
+// which is the largest possible encoding of the signatures. Each additional
+// signature placed in a shared credential reduces the actual size by 8 bytes:
+// 4 for the credential's type ID and 4 for its signature count.
+func EstimateSize(utx UnsignedTx, numSigs int) (int, error) {
+	if numSigs < 0 {
+		return 0, fmt.Errorf("%w: %d", errNegativeNumSigs, numSigs)
+	}
+
+	creds := make([]verify.Verifiable, numSigs)
+	for i := range creds {
+		creds[i] = &secp256k1fx.Credential{
+			Sigs: make([][secp256k1.SignatureLen]byte, 1),
+		}
+	}
+	return Codec.Size(CodecVersion, &Tx{
+		Unsigned: utx,
+		Creds:    creds,
+	})
+}
+
 func (tx *Tx) Initialize(c codec.Manager) error {
 	signedBytes, err := c.Marshal(CodecVersion, tx)
 	if err != nil {
@@ -0,0 +1,163 @@
+// Copyright (C) 2019-2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package txs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestEstimateSize(t *testing.T) {
+	var (
+		assetID = ids.GenerateTestID()
+		owner   = secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{preFundedKeys[0].Address()},
+		}
+		baseTx = BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    1,
+			BlockchainID: ids.GenerateTestID(),
+			Ins: []*avax.TransferableInput{
+				{
+					UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+					Asset:  avax.Asset{ID: assetID},
+					In: &secp256k1fx.TransferInput{
+						Amt:   5678,
+						Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+					},
+				},
+				{
+					UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+					Asset:  avax.Asset{ID: assetID},
+					In: &secp256k1fx.TransferInput{
+						Amt:   1234,
+						Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+					},
+				},
+			},
+			Outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: assetID},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:          1234,
+					OutputOwners: owner,
+				},
+			}},
+			Memo: []byte("memo"),
+		}}
+		signers = [][]*secp256k1.PrivateKey{
+			{preFundedKeys[0]},
+			{preFundedKeys[1]},
+		}
+	)
+
+	tests := []struct {
+		name       string
+		unsignedTx UnsignedTx
+		signers    [][]*secp256k1.PrivateKey
+	}{
+		{
+			name:       "BaseTx",
+			unsignedTx: &baseTx,
+			signers:    signers,
+		},
+		{
+			name: "AddValidatorTx",
+			unsignedTx: &AddValidatorTx{
+				BaseTx: baseTx,
+				Validator: Validator{
+					NodeID: ids.GenerateTestNodeID(),
+					Start:  1,
+					End:    2,
+					Wght:   2022,
+				},
+				StakeOuts: []*avax.TransferableOutput{{
+					Asset: avax.Asset{ID: assetID},
+					Out: &secp256k1fx.TransferOutput{
+						Amt:          2022,
+						OutputOwners: owner,
+					},
+				}},
+				RewardsOwner:     &owner,
+				DelegationShares: reward.PercentDenominator,
+			},
+			signers: signers,
+		},
+		{
+			name: "CreateSubnetTx",
+			unsignedTx: &CreateSubnetTx{
+				BaseTx: baseTx,
+				Owner:  &owner,
+			},
+			signers: signers,
+		},
+		{
+			name: "RemoveSubnetValidatorTx",
+			unsignedTx: &RemoveSubnetValidatorTx{
+				BaseTx: baseTx,
+				NodeID: ids.GenerateTestNodeID(),
+				Subnet: ids.GenerateTestID(),
+				SubnetAuth: &secp256k1fx.Input{
+					SigIndices: []uint32{0},
+				},
+			},
+			signers: [][]*secp256k1.PrivateKey{
+				{preFundedKeys[0]},
+				{preFundedKeys[1]},
+				{preFundedKeys[2]},
+			},
+		},
+		{
+			name:       "no signatures",
+			unsignedTx: &baseTx,
+			signers:    nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			estimatedSize, err := EstimateSize(test.unsignedTx, len(test.signers))
+			require.NoError(err)
+
+			tx, err := NewSigned(test.unsignedTx, Codec, test.signers)
+			require.NoError(err)
+			require.Equal(len(tx.Bytes()), estimatedSize)
+		})
+	}
+}
+
+func TestEstimateSizeSharedCredential(t *testing.T) {
+	require := require.New(t)
+
+	utx := &CreateSubnetTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    1,
+			BlockchainID: ids.GenerateTestID(),
+		}},
+		Owner: &secp256k1fx.OutputOwners{},
+	}
+
+	estimatedSize, err := EstimateSize(utx, 2)
+	require.NoError(err)
+
+	// Placing both signatures in a single credential omits one credential type
+	// ID and one signature count.
+	tx, err := NewSigned(utx, Codec, [][]*secp256k1.PrivateKey{
+		{preFundedKeys[0], preFundedKeys[1]},
+	})
+	require.NoError(err)
+	require.Equal(len(tx.Bytes())+8, estimatedSize)
+}
+
+func TestEstimateSizeNegativeNumSigs(t *testing.T) {
+	_, err := EstimateSize(&CreateSubnetTx{}, -1)
+	require.ErrorIs(t, err, errNegativeNumSigs)
+}
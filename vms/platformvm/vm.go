@@ -133,7 +133,10 @@ func (vm *VM) Initialize(
 		return err
 	}
 
-	rewards := reward.NewCalculator(vm.RewardConfig)
+	rewards, err := reward.NewCalculator(vm.RewardConfig)
+	if err != nil {
+		return fmt.Errorf("invalid reward config: %w", err)
+	}
 
 	vm.state, err = state.New(
 		vm.db,
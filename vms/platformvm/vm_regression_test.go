@@ -631,12 +631,14 @@ func TestRejectedStateRegressionInvalidValidatorTimestamp(t *testing.T) {
 
 	// Force a reload of the state from the database.
 	vm.Config.Validators = validators.NewManager()
+	rewards, err := reward.NewCalculator(vm.Config.RewardConfig)
+	require.NoError(err)
 	newState := statetest.New(t, statetest.Config{
 		DB:         vm.db,
 		Validators: vm.Config.Validators,
 		Upgrades:   vm.Config.UpgradeConfig,
 		Context:    vm.ctx,
-		Rewards:    reward.NewCalculator(vm.Config.RewardConfig),
+		Rewards:    rewards,
 	})
 
 	// Verify that new validator is now in the current validator set.
@@ -923,12 +925,14 @@ func TestRejectedStateRegressionInvalidValidatorReward(t *testing.T) {
 
 	// Force a reload of the state from the database.
 	vm.Config.Validators = validators.NewManager()
+	rewards, err := reward.NewCalculator(vm.Config.RewardConfig)
+	require.NoError(err)
 	newState := statetest.New(t, statetest.Config{
 		DB:         vm.db,
 		Validators: vm.Config.Validators,
 		Upgrades:   vm.Config.UpgradeConfig,
 		Context:    vm.ctx,
-		Rewards:    reward.NewCalculator(vm.Config.RewardConfig),
+		Rewards:    rewards,
 	})
 
 	// Verify that validators are in the current validator set with the correct